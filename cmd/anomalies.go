@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/anomaly"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	anomalyStartDate  string
+	anomalyEndDate    string
+	anomalyReportType string
+)
+
+func init() {
+	anomaliesCmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "Detect anomalous days in daily cost and usage",
+		Long: `Score every day of every account/service cost series and account/service/metric
+usage series against its trailing window with robust statistics, and list the
+anomalous days with their severity, expected and actual values, and the items that
+contributed most to cost anomalies.
+
+Methods:
+  seasonal  remove the day-of-week pattern, then compare with the median (default)
+  mad       compare with the rolling median, scaled by the median absolute deviation
+
+History is fetched from --window days before --start-date so the first days of the
+period can be scored too.`,
+		Example: `  observability-cost-center anomalies --provider aws
+  observability-cost-center anomalies --provider aws --method mad --window 14 --output json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeAnomalies(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error detecting anomalies: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	defaults := anomaly.DefaultOptions()
+	anomaliesCmd.Flags().StringVar(&anomalyStartDate, "start-date", time.Now().AddDate(0, 0, -14).Format("2006-01-02"), "First day to check for anomalies (YYYY-MM-DD)")
+	anomaliesCmd.Flags().StringVar(&anomalyEndDate, "end-date", time.Now().Format("2006-01-02"), "End of the period to check (YYYY-MM-DD, exclusive)")
+	anomaliesCmd.Flags().StringVar(&anomalyReportType, "type", "full", "Series to check: usage, cost, or full")
+	anomaliesCmd.Flags().String("method", defaults.Method, "Detection method: seasonal or mad")
+	anomaliesCmd.Flags().Int("window", defaults.Window, "Trailing days each day is compared with")
+	anomaliesCmd.Flags().Float64("threshold", defaults.Threshold, "Robust z-score from which a day is anomalous (1.5x is a warning, 2x critical)")
+	anomaliesCmd.Flags().Float64("min-change", defaults.MinChange, "Ignore deviations smaller than this share of the expected value")
+	anomaliesCmd.Flags().Float64("min-cost", defaults.MinCost, "Ignore cost deviations smaller than this amount")
+	anomaliesCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("anomalies.method", anomaliesCmd.Flags().Lookup("method"))
+	viper.BindPFlag("anomalies.window", anomaliesCmd.Flags().Lookup("window"))
+	viper.BindPFlag("anomalies.threshold", anomaliesCmd.Flags().Lookup("threshold"))
+	viper.BindPFlag("anomalies.min_change", anomaliesCmd.Flags().Lookup("min-change"))
+	viper.BindPFlag("anomalies.min_cost", anomaliesCmd.Flags().Lookup("min-cost"))
+
+	rootCmd.AddCommand(anomaliesCmd)
+}
+
+func executeAnomalies() error {
+	start, end, err := parseReportPeriod(anomalyStartDate, anomalyEndDate)
+	if err != nil {
+		return err
+	}
+	typ, err := parseReportType(anomalyReportType)
+	if err != nil {
+		return err
+	}
+
+	opts := anomalyOptionsFromConfig()
+	opts.Since = start
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+
+	report, err := reports.NewReportGenerator(costProvider).GenerateReport(typ, start.AddDate(0, 0, -opts.Window), end)
+	if err != nil {
+		return fmt.Errorf("error generating report: %w", err)
+	}
+
+	section, err := anomalySection(report, opts)
+	if err != nil {
+		return err
+	}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return reports.OutputSections(format, outputFile, section)
+}
+
+// anomalyOptionsFromConfig reads the detection options from flags or the config
+func anomalyOptionsFromConfig() anomaly.Options {
+	return anomaly.Options{
+		Method:    viper.GetString("anomalies.method"),
+		Window:    viper.GetInt("anomalies.window"),
+		Threshold: viper.GetFloat64("anomalies.threshold"),
+		MinChange: viper.GetFloat64("anomalies.min_change"),
+		MinCost:   viper.GetFloat64("anomalies.min_cost"),
+	}
+}
+
+// anomalySection detects anomalies in the report's cost and usage series
+func anomalySection(report *reports.Report, opts anomaly.Options) (providers.Section, error) {
+	series := anomaly.CostSeries(report.CostData, report.StartDate, report.EndDate)
+	series = append(series, anomaly.UsageSeries(report.UsageData, report.StartDate, report.EndDate)...)
+
+	anomalies, err := anomaly.Detect(series, opts)
+	if err != nil {
+		return providers.Section{}, err
+	}
+	slog.Info("detected anomalies", "series", len(series), "anomalies", len(anomalies))
+
+	return anomaly.Section(anomalies, series, opts), nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/budget"
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Exit code of "budget check" when a budget is breached at the --fail-on level.
+// Errors exit with 1, like every other command.
+const exitBudgetBreached = 2
+
+var (
+	budgetAsOf        string
+	budgetHistoryDays int
+)
+
+func init() {
+	budgetCmd := &cobra.Command{
+		Use:   "budget",
+		Short: "Manage spend budgets",
+		Long:  `Evaluate the monthly spend budgets declared under budgets in the configuration.`,
+	}
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check spend against the configured budgets",
+		Long: `Fetch the cost of the current month and evaluate every budget declared in the
+configuration. Budgets are scoped to a provider, account, service or team and are
+evaluated on the actual spend of the month to date or on the forecast month-end
+spend, which reports a breach before it happens.
+
+A budget is a warning from warn_pct of its amount and critical from critical_pct.
+The command exits with status 2 when a budget reaches the --fail-on level, so it
+can gate CI pipelines and cron jobs, and with status 1 when it fails.`,
+		Example: `  observability-cost-center budget check
+  observability-cost-center budget check --fail-on warning --output json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			breached, err := executeBudgetCheck()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking budgets: %v\n", err)
+				os.Exit(1)
+			}
+			if breached {
+				os.Exit(exitBudgetBreached)
+			}
+		},
+	}
+
+	checkCmd.Flags().StringVar(&budgetAsOf, "as-of", time.Now().Format("2006-01-02"), "Check the month of this date (YYYY-MM-DD); cost ends the day before")
+	checkCmd.Flags().IntVar(&budgetHistoryDays, "history-days", 90, "Days of cost history to fit the forecast models on, for forecast budgets")
+	checkCmd.Flags().String("fail-on", budget.StatusCritical, "Exit with status 2 from this status: warning, critical or never")
+	checkCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("budget.fail_on", checkCmd.Flags().Lookup("fail-on"))
+
+	budgetCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+// executeBudgetCheck evaluates the budgets and reports whether one was breached at
+// the --fail-on level
+func executeBudgetCheck() (bool, error) {
+	asOf, err := time.Parse("2006-01-02", budgetAsOf)
+	if err != nil {
+		return false, fmt.Errorf("error parsing as-of date: %w", err)
+	}
+	if budgetHistoryDays <= 0 {
+		return false, fmt.Errorf("history-days must be positive")
+	}
+	failOn := viper.GetString("budget.fail_on")
+	if failOn != budget.StatusWarning && failOn != budget.StatusCritical && failOn != "never" {
+		return false, fmt.Errorf("unsupported fail-on level %q (use warning, critical or never)", failOn)
+	}
+
+	cfg, err := budget.ConfigFromViper(viper.GetViper(), viper.GetString("provider"))
+	if err != nil {
+		return false, err
+	}
+	if len(cfg.Budgets) == 0 {
+		return false, fmt.Errorf("no budgets configured; declare them under budgets in the config file")
+	}
+
+	// Fetch each provider's cost once, going back far enough for its forecast budgets
+	month := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	starts := make(map[string]time.Time)
+	for _, b := range cfg.Budgets {
+		start := month
+		if b.Basis == budget.BasisForecast {
+			start = asOf.AddDate(0, 0, -budgetHistoryDays)
+		}
+		if current, ok := starts[b.Provider]; !ok || start.Before(current) {
+			starts[b.Provider] = start
+		}
+	}
+
+	names := make([]string, 0, len(starts))
+	for name := range starts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	costs := make(map[string][]providers.CostData, len(starts))
+	for _, name := range names {
+		// Nothing to fetch on the first day of the month for actual budgets
+		if !starts[name].Before(asOf) {
+			continue
+		}
+		costProvider, err := newProvider(name)
+		if err != nil {
+			return false, err
+		}
+		report, err := reports.NewReportGenerator(costProvider).GenerateReport(reports.CostReport, starts[name], asOf)
+		if err != nil {
+			return false, fmt.Errorf("error generating %s report: %w", name, err)
+		}
+		costs[name] = report.CostData
+		slog.Info("fetched cost for budgets", "provider", name, "costEntries", len(report.CostData), "startDate", starts[name].Format("2006-01-02"))
+	}
+
+	opts := forecast.Options{
+		Confidence:   viper.GetFloat64("forecast.confidence"),
+		Backtests:    viper.GetInt("forecast.backtests"),
+		BacktestDays: viper.GetInt("forecast.backtest_days"),
+	}
+	check := &reports.BudgetCheck{AsOf: asOf}
+	for _, b := range cfg.Budgets {
+		scoped := b.Filter(costs[b.Provider], cfg.Teams)
+		check.Statuses = append(check.Statuses, budget.Evaluate(b, scoped, starts[b.Provider], asOf, opts))
+	}
+	budget.Sort(check.Statuses)
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	if err := check.Output(format, outputFile); err != nil {
+		return false, err
+	}
+
+	if err := budget.Errors(check.Statuses); err != nil {
+		return false, err
+	}
+	return failOn != "never" && budget.Breached(check.Statuses, failOn), nil
+}
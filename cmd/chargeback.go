@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/allocation"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var chargebackPeriod string
+
+func init() {
+	chargebackCmd := &cobra.Command{
+		Use:   "chargeback",
+		Short: "Allocate cost to teams with allocation rules",
+		Long: `Fetch the cost of a period and allocate it to teams with the declarative rules
+under allocation.rules in the configuration, or in a standalone --rules file.
+
+Rules are evaluated in order and the first one matching a line item's provider,
+account, service, item or region wins. A rule assigns the cost to one team, or
+splits it evenly, by fixed weights, or by each team's share of usage broken down by
+log group, New Relic app, user group or cost allocation tag. Cost that no rule
+places is reported as Unallocated, with the reason.`,
+		Example: `  observability-cost-center chargeback --period 2026-09
+  observability-cost-center chargeback --rules allocation.yaml --output csv --output-file chargeback.csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeChargeback(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating chargeback: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	chargebackCmd.Flags().StringVar(&chargebackPeriod, "period", time.Now().AddDate(0, -1, 0).Format("2006-01"), "Period to allocate (YYYY-MM, YYYY-MM-DD..YYYY-MM-DD or last-<N>d)")
+	chargebackCmd.Flags().String("rules", "", "YAML file with the allocation rules (default: allocation.rules in the config)")
+	chargebackCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("allocation.rules_file", chargebackCmd.Flags().Lookup("rules"))
+
+	rootCmd.AddCommand(chargebackCmd)
+}
+
+func executeChargeback() error {
+	period, err := reports.ParsePeriod(chargebackPeriod, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	var rules []allocation.Rule
+	if path := viper.GetString("allocation.rules_file"); path != "" {
+		rules, err = allocation.LoadRulesFile(path)
+	} else {
+		rules, err = allocation.RulesFromViper(viper.GetViper())
+	}
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no allocation rules configured; declare them under allocation.rules or pass --rules")
+	}
+
+	names := chargebackProviders(rules, viper.GetString("provider"))
+	if len(names) == 0 {
+		return fmt.Errorf("provider is required. Use --provider flag, set it in config or name providers in the rules")
+	}
+
+	var lines []allocation.Line
+	for _, name := range names {
+		costProvider, err := newProvider(name)
+		if err != nil {
+			return err
+		}
+		report, err := reports.NewReportGenerator(costProvider).GenerateReport(reports.CostReport, period.Start, period.LastDay())
+		if err != nil {
+			return fmt.Errorf("error generating %s report: %w", name, err)
+		}
+
+		usage := make(allocation.Usage)
+		for _, dimension := range allocation.UsageDimensions(rules, name) {
+			dimensionProvider, ok := costProvider.(providers.DimensionUsageProvider)
+			if !ok {
+				slog.Warn("provider cannot break usage down by dimension; its usage splits stay unallocated", "provider", name, "dimension", dimension)
+				continue
+			}
+			usage[dimension], err = dimensionProvider.GetDimensionUsage(dimension, period.Start, period.LastDay())
+			if err != nil {
+				slog.Warn("error fetching usage by dimension; its usage splits stay unallocated", "provider", name, "dimension", dimension, "error", err)
+			}
+		}
+
+		slog.Info("allocating cost", "provider", name, "costEntries", len(report.CostData), "period", period.String())
+		lines = append(lines, allocation.Allocate(rules, name, report.CostData, usage)...)
+	}
+
+	statement := &reports.Chargeback{Statement: allocation.NewStatement(period.Start, period.End, lines)}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return statement.Output(format, outputFile)
+}
+
+// chargebackProviders returns the configured provider and every provider named
+// literally in the rules
+func chargebackProviders(rules []allocation.Rule, configured string) []string {
+	seen := make(map[string]bool)
+	if configured != "" {
+		seen[strings.ToLower(configured)] = true
+	}
+	for _, r := range rules {
+		if r.Match.Provider != "" && !strings.Contains(r.Match.Provider, "*") {
+			seen[strings.ToLower(r.Match.Provider)] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
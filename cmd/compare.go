@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	comparePeriod  string
+	compareAgainst string
+	compareTop     int
+)
+
+func init() {
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare cost between two periods",
+		Long: `Generate cost reports for two periods and compare them line item by line item
+(provider, account, service and item). Shows absolute and percent changes, new and
+disappeared line items, and the top increases.
+
+Periods are a calendar month (2026-09), a date range with an exclusive end
+(2026-09-01..2026-09-15) or a rolling window ending today (last-30d, last-4w).
+--against defaults to the period right before --period.`,
+		Example: `  observability-cost-center compare --period 2026-09 --against 2026-08
+  observability-cost-center compare --period last-7d --output markdown`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeCompare(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error comparing periods: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	compareCmd.Flags().StringVar(&comparePeriod, "period", time.Now().AddDate(0, -1, 0).Format("2006-01"), "Period to compare (YYYY-MM, YYYY-MM-DD..YYYY-MM-DD or last-<N>d)")
+	compareCmd.Flags().StringVar(&compareAgainst, "against", "", "Period to compare against (default: the period before --period)")
+	compareCmd.Flags().IntVar(&compareTop, "top", 10, "Number of top increases to show")
+	compareCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	rootCmd.AddCommand(compareCmd)
+}
+
+func executeCompare() error {
+	now := time.Now().UTC()
+	period, err := reports.ParsePeriod(comparePeriod, now)
+	if err != nil {
+		return err
+	}
+	against := period.Previous()
+	if compareAgainst != "" {
+		if against, err = reports.ParsePeriod(compareAgainst, now); err != nil {
+			return err
+		}
+	}
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+	generator := reports.NewReportGenerator(costProvider)
+
+	slog.Info("generating reports", "period", period.String(), "against", against.String())
+	current, err := generator.GenerateReport(reports.CostReport, period.Start, period.LastDay())
+	if err != nil {
+		return fmt.Errorf("error generating report for %s: %w", period, err)
+	}
+	previous, err := generator.GenerateReport(reports.CostReport, against.Start, against.LastDay())
+	if err != nil {
+		return fmt.Errorf("error generating report for %s: %w", against, err)
+	}
+
+	comparison := reports.CompareReports(current, previous)
+	comparison.Current = period
+	comparison.Previous = against
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return comparison.Output(format, outputFile, compareTop)
+}
@@ -67,15 +67,142 @@ aws:
   # Alternatively, specify credentials directly (not recommended)
   # access_key_id: YOUR_ACCESS_KEY
   # secret_access_key: YOUR_SECRET_KEY
+  # Where cost comes from: auto (Cost Explorer, estimated from usage when it has no
+  # data), billing (Cost Explorer only) or estimate (always estimated from usage)
+  # cost_source: auto
+  # CloudWatch Logs prices overriding the pricing catalog in this region
+  # pricing:
+  #   logs_ingest_per_gb: 0.50
+  #   logs_infrequent_access_ingest_per_gb: 0.25
+  #   logs_storage_per_gb_month: 0.03
 
 # NewRelic Provider Configuration
 newrelic:
-  # Your New Relic Account ID
+  # Your New Relic Account ID (only this account is queried when set)
   account_id: YOUR_ACCOUNT_ID
+  # Alternatively, restrict queries with allow/deny lists of account IDs
+  # account_ids:
+  #   allow: [1234567, 2345678]
+  #   deny: [3456789]
+  # Where organization-level license cost is attributed:
+  # organization (default), account (requires exactly one allowed account) or none
+  # license_attribution: organization
+  # Data option used to price ingest and extended retention (original or data_plus)
+  # data_option: original
+  # Where data cost comes from: auto (NrConsumption, estimated from usage when it has
+  # no data), billing (NrConsumption only) or estimate (always estimated from usage)
+  # cost_source: auto
+  # Maximum retention allowed per namespace, audited in New Relic reports
+  # retention:
+  #   policy:
+  #     default: 90
+  #     namespaces:
+  #       Logging: 30
   # New Relic API Key is recommended to be set via environment variable:
   # export NEW_RELIC_API_KEY=your_api_key
   # Alternatively, specify here (not recommended)
   # api_key: YOUR_API_KEY
+
+# OpenTelemetry Collector used by "export otlp" (optional)
+# otlp:
+#   endpoint: collector.example.com:4317
+#   protocol: grpc   # grpc or http
+#   insecure: false
+#   headers:
+#     api-key: YOUR_INGEST_KEY
+
+# Spend forecasts made by "forecast" (optional)
+# forecast:
+#   confidence: 0.8     # coverage of the confidence bands
+#   backtests: 3        # backtests per model
+#   backtest_days: 7    # days held out in each backtest
+
+# Anomaly detection used by "anomalies" and "report --anomalies" (optional)
+# anomalies:
+#   enabled: false      # add the anomaly section to every report
+#   method: seasonal    # seasonal or mad
+#   window: 28          # trailing days each day is compared with
+#   threshold: 3.5      # robust z-score; 1.5x is a warning, 2x critical
+#   min_change: 0.2     # ignore deviations below 20% of the expected value
+#   min_cost: 1         # ignore cost deviations below this amount
+
+# Teams own the cost of their accounts, and of their services in any account (optional)
+# teams:
+#   payments:
+#     accounts: ["123456789012"]
+#     services: [AmazonCloudWatch]
+
+# Monthly budgets checked by "budget check" (optional)
+# budget:
+#   fail_on: critical   # exit with status 2 from this status: warning, critical or never
+# budgets:
+#   - name: payments-observability
+#     provider: aws     # defaults to the configured provider
+#     account: ""       # scope: account, service and/or team; empty matches all
+#     service: ""
+#     team: payments
+#     amount: 5000      # per month
+#     warn_pct: 80
+#     critical_pct: 100
+#     basis: actual     # actual (month to date) or forecast (projected month-end)
+
+# Chargeback allocation rules used by "chargeback" (optional); first match wins
+# allocation:
+#   rules_file: ""      # or keep the rules in a standalone YAML file
+#   rules:
+#     - name: payments-account
+#       match: {provider: aws, account: "123456789012"}
+#       team: payments
+#     - name: logs-by-log-group
+#       match: {provider: aws, service: AmazonCloudWatch}
+#       split:
+#         by: usage     # even (teams), weights (weights) or usage (dimension)
+#         dimension: logGroup
+#         mapping:
+#           /aws/lambda/payments-*: payments
+
+# Unit economics KPIs computed by "unit-costs" (optional; built-in KPIs otherwise)
+# unit_economics:
+#   business_csv: business.csv   # period,metric,value rows, e.g. 2026-09,requests,125000000
+#   kpis:
+#     - name: cost_per_million_requests
+#       cost: {service: "*"}
+#       business: requests
+#       per: 1000000
+#       unit: requests
+
+# Prices used to estimate cost from usage (optional). The JSON or CSV file is merged
+# over the built-in catalog; export it with "pricing list --output csv"
+# pricing:
+#   catalog: prices.csv
+
+# Discrepancies flagged by "reconcile" (optional)
+# reconcile:
+#   tolerance: 5     # percent of the expected cost a difference may reach
+#   min_delta: 1     # ignore smaller differences
+
+# What-if scenarios projected by "simulate" (optional)
+# simulate:
+#   scenarios:
+#     - name: lambda-14d
+#       type: retention        # retention, drop, downgrade or log_class
+#       match: /aws/lambda/*
+#       days: 14
+#     - name: drop-log-events
+#       type: drop
+#       provider: newrelic
+#       account: "1234567"
+#       match: Log
+#       percent: 30
+#     - name: full-to-basic
+#       type: downgrade
+#       from: Full platform
+#       to: Basic
+#       count: 20
+#     - name: flow-logs-ia
+#       type: log_class
+#       match: /vpc/flow-logs/*
+#       to: INFREQUENT_ACCESS
 `
 
 	// Ensure directory exists
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/export"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	exportStartDate  string
+	exportEndDate    string
+	exportReportType string
+)
+
+func init() {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Send report data to external systems",
+		Long:  `Generate a report and send its usage and cost data to an external system instead of printing it.`,
+	}
+
+	otlpCmd := &cobra.Command{
+		Use:   "otlp",
+		Short: "Push usage and cost as OTLP metrics to an OpenTelemetry Collector",
+		Long: `Generate a report and push its usage and cost data as OTLP metrics over gRPC or HTTP.
+Each provider/account pair is sent as its own resource, identified by the
+observability.provider and observability.account.id resource attributes.
+
+The endpoint defaults to the OTLP exporter default (localhost:4317 for gRPC,
+localhost:4318 for HTTP) and honors the standard OTEL_EXPORTER_OTLP_* variables.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeExportOTLP(cmd.Context()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting OTLP metrics: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	otlpCmd.Flags().StringVar(&exportStartDate, "start-date", time.Now().AddDate(0, -1, 0).Format("2006-01-02"), "Start date for the report (YYYY-MM-DD)")
+	otlpCmd.Flags().StringVar(&exportEndDate, "end-date", time.Now().Format("2006-01-02"), "End date for the report (YYYY-MM-DD)")
+	otlpCmd.Flags().StringVar(&exportReportType, "type", "full", "Report type: usage, cost, or full")
+	otlpCmd.Flags().String("endpoint", "", "Collector endpoint, host:port or a full URL")
+	otlpCmd.Flags().String("protocol", export.ProtocolGRPC, "OTLP transport: grpc or http")
+	otlpCmd.Flags().Bool("insecure", false, "Connect without TLS")
+	otlpCmd.Flags().StringToString("header", nil, "Header sent with every request (key=value, repeatable)")
+	otlpCmd.Flags().Duration("timeout", 10*time.Second, "Timeout for each export request")
+
+	viper.BindPFlag("otlp.endpoint", otlpCmd.Flags().Lookup("endpoint"))
+	viper.BindPFlag("otlp.protocol", otlpCmd.Flags().Lookup("protocol"))
+	viper.BindPFlag("otlp.insecure", otlpCmd.Flags().Lookup("insecure"))
+	viper.BindPFlag("otlp.headers", otlpCmd.Flags().Lookup("header"))
+	viper.BindPFlag("otlp.timeout", otlpCmd.Flags().Lookup("timeout"))
+
+	exportCmd.AddCommand(otlpCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func executeExportOTLP(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	start, end, err := parseReportPeriod(exportStartDate, exportEndDate)
+	if err != nil {
+		return err
+	}
+
+	typ, err := parseReportType(exportReportType)
+	if err != nil {
+		return err
+	}
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+
+	report, err := reports.NewReportGenerator(costProvider).GenerateReport(typ, start, end)
+	if err != nil {
+		return fmt.Errorf("error generating report: %w", err)
+	}
+
+	opts := export.OTLPOptions{
+		Endpoint: viper.GetString("otlp.endpoint"),
+		Protocol: viper.GetString("otlp.protocol"),
+		Insecure: viper.GetBool("otlp.insecure"),
+		Headers:  viper.GetStringMapString("otlp.headers"),
+		Timeout:  viper.GetDuration("otlp.timeout"),
+	}
+
+	exporter, err := export.NewOTLPExporter(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("error creating OTLP exporter: %w", err)
+	}
+
+	points, exportErr := export.ExportReport(ctx, exporter, report)
+	if err := exporter.Shutdown(ctx); err != nil && exportErr == nil {
+		exportErr = fmt.Errorf("error shutting down OTLP exporter: %w", err)
+	}
+	if exportErr != nil {
+		return exportErr
+	}
+
+	slog.Info("exported report as OTLP metrics", "provider", report.ProviderName, "dataPoints", points, "protocol", opts.Protocol)
+	return nil
+}
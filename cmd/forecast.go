@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	forecastHistoryDays int
+	forecastAsOf        string
+	forecastService     string
+	forecastAccount     string
+)
+
+func init() {
+	forecastCmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Forecast month-end and next-month spend from daily cost history",
+		Long: `Fetch the daily cost history and project the spend of the current month and the next
+one with three models: a linear trend, damped exponential smoothing (Holt) and a
+weekday-seasonal trend. Each projection has a confidence band, and each model is
+backtested on the most recent weeks of history so you can see which one to trust.
+
+Forecasts only need daily cost history, so they work for providers without a native
+forecast API. Providers that report one total per month, like New Relic, are refused.`,
+		Example: `  observability-cost-center forecast --provider aws
+  observability-cost-center forecast --provider aws --history-days 60 --confidence 0.95`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeForecast(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error forecasting spend: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	defaults := forecast.DefaultOptions()
+	forecastCmd.Flags().IntVar(&forecastHistoryDays, "history-days", 90, "Days of cost history to fit the models on")
+	forecastCmd.Flags().StringVar(&forecastAsOf, "as-of", time.Now().Format("2006-01-02"), "Forecast from this date (YYYY-MM-DD); history ends the day before")
+	forecastCmd.Flags().StringVar(&forecastService, "service", "", "Only forecast this service")
+	forecastCmd.Flags().StringVar(&forecastAccount, "account", "", "Only forecast this account")
+	forecastCmd.Flags().Float64("confidence", defaults.Confidence, "Coverage of the confidence bands (0-1)")
+	forecastCmd.Flags().Int("backtests", defaults.Backtests, "Number of backtests per model")
+	forecastCmd.Flags().Int("backtest-days", defaults.BacktestDays, "Days held out in each backtest")
+	forecastCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("forecast.confidence", forecastCmd.Flags().Lookup("confidence"))
+	viper.BindPFlag("forecast.backtests", forecastCmd.Flags().Lookup("backtests"))
+	viper.BindPFlag("forecast.backtest_days", forecastCmd.Flags().Lookup("backtest-days"))
+
+	rootCmd.AddCommand(forecastCmd)
+}
+
+func executeForecast() error {
+	asOf, err := time.Parse("2006-01-02", forecastAsOf)
+	if err != nil {
+		return fmt.Errorf("error parsing as-of date: %w", err)
+	}
+	if forecastHistoryDays <= 0 {
+		return fmt.Errorf("history-days must be positive")
+	}
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+
+	report, err := reports.NewReportGenerator(costProvider).GenerateReport(reports.CostReport, asOf.AddDate(0, 0, -forecastHistoryDays), asOf)
+	if err != nil {
+		return fmt.Errorf("error generating report: %w", err)
+	}
+	report.CostData = filterCosts(report.CostData, forecastService, forecastAccount)
+	slog.Info("forecasting spend", "provider", report.ProviderName, "costEntries", len(report.CostData), "historyDays", forecastHistoryDays)
+
+	opts := forecast.Options{
+		Confidence:   viper.GetFloat64("forecast.confidence"),
+		Backtests:    viper.GetInt("forecast.backtests"),
+		BacktestDays: viper.GetInt("forecast.backtest_days"),
+	}
+	result, err := report.NewForecast(forecast.Models(), opts)
+	if err != nil {
+		return err
+	}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return result.Output(format, outputFile)
+}
+
+// filterCosts keeps the cost entries of the given service and account; empty
+// filters match everything
+func filterCosts(costs []providers.CostData, service, account string) []providers.CostData {
+	if service == "" && account == "" {
+		return costs
+	}
+	var filtered []providers.CostData
+	for _, c := range costs {
+		if service != "" && !strings.EqualFold(c.Service, service) {
+			continue
+		}
+		if account != "" && c.AccountID != account {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	pricingCmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Inspect the pricing catalog used to estimate cost from usage",
+		Long: `The pricing catalog holds the list prices used to estimate cost from usage when a
+provider's billing API is not available, e.g. when AWS Cost Explorer is not enabled.
+
+A catalog of CloudWatch and New Relic list prices is built in. Prices in the JSON or
+CSV file set as pricing.catalog (or --catalog) are merged over it, replacing the
+built-in price for the same provider, region, option, service and metric. Export the
+catalog with "pricing list --output csv" to start a file of your own prices.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the prices of the catalog",
+		Example: `  observability-cost-center pricing list --provider aws
+  observability-cost-center pricing list --output csv --output-file prices.csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executePricingList(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing prices: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	pricingCmd.PersistentFlags().String("catalog", "", "JSON or CSV file of prices merged over the built-in catalog")
+	listCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("pricing.catalog", pricingCmd.PersistentFlags().Lookup("catalog"))
+
+	pricingCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(pricingCmd)
+}
+
+func executePricingList() error {
+	catalog, err := pricing.FromConfig(viper.GetViper())
+	if err != nil {
+		return err
+	}
+
+	// Only list the prices of the selected provider
+	if name := viper.GetString("provider"); name != "" {
+		catalog = &pricing.Catalog{Version: catalog.Version, Prices: catalog.Provider(name)}
+	}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	list := &reports.PriceList{Catalog: catalog}
+	if err := list.Output(format, outputFile); err != nil {
+		return fmt.Errorf("error writing prices: %w", err)
+	}
+	return nil
+}
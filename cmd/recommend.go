@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers/newrelic"
+	"github.com/ilhicas/observability-cost-center/internal/recommend"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	applyDropRules     bool
+	dropRuleLookback   int
+	dropRuleEventTypes int
+	dropRuleAttributes int
+	dropRuleMinGB      float64
+	dropRuleAccount    string
+	dropRuleIDs        []string
+	dropRulesYes       bool
+
+	recommendRules         []string
+	recommendMinSavings    float64
+	recommendMinConfidence string
+)
+
+func init() {
+	recommendCmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Recommend changes that reduce observability cost",
+		Long: `Analyze provider data and recommend changes that reduce observability cost.
+
+Without a subcommand, every savings rule registered for the provider is evaluated and
+the recommendations are listed, largest monthly savings first. Each recommendation
+names the resource, the action to take, the estimated monthly savings, a confidence
+level and the evidence behind it. Recommendations for the same resource found more
+than once are merged. Use "recommend rules" to list the available rules.`,
+		Example: `  observability-cost-center recommend --provider newrelic
+  observability-cost-center recommend --provider newrelic --min-savings 100 --min-confidence high --output csv --output-file savings.csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeRecommend(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error recommending savings: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	recommendCmd.Flags().StringSliceVar(&recommendRules, "rule", nil, "Only evaluate these rules (repeatable, default all rules of the provider)")
+	recommendCmd.Flags().Float64Var(&recommendMinSavings, "min-savings", 0, "Skip recommendations saving less than this per month")
+	recommendCmd.Flags().StringVar(&recommendMinConfidence, "min-confidence", "", "Skip recommendations below this confidence (low, medium, high)")
+	recommendCmd.Flags().Int("inactive-days", 30, "Days without activity after which a New Relic license is reclaimable")
+	recommendCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("newrelic.inactive_days", recommendCmd.Flags().Lookup("inactive-days"))
+
+	rulesCmd := &cobra.Command{
+		Use:   "rules",
+		Short: "List the registered savings recommendation rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			writeRecommendationRules(os.Stdout, recommend.Rules(viper.GetString("provider")))
+		},
+	}
+
+	dropRulesCmd := &cobra.Command{
+		Use:   "drop-rules",
+		Short: "Propose New Relic NRQL drop rules for the noisiest data",
+		Long: `Estimate which New Relic event types and attributes cost the most and propose NRQL drop
+rules for them, as nrqlDropRulesCreate mutations. Runs as a dry run unless --apply is given.
+
+--apply only creates rules in one account: --account, or the configured
+newrelic.account_id. --rule selects proposals by the ID shown in the dry-run list,
+which stays the same between runs however the proposals are ranked. Before creating
+anything, --apply prints the exact NRQL of each rule and asks for confirmation, unless
+--yes is given.`,
+		Example: `  observability-cost-center recommend drop-rules --account 1234567
+  observability-cost-center recommend drop-rules --account 1234567 --apply --rule 3f9a12c4 --rule 0b7d5e21`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeDropRules(os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error recommending drop rules: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	defaults := newrelic.DefaultDropRuleOptions()
+	dropRulesCmd.Flags().BoolVar(&applyDropRules, "apply", false, "Create the proposed drop rules in New Relic instead of only printing them")
+	dropRulesCmd.Flags().IntVar(&dropRuleLookback, "lookback-days", defaults.LookbackDays, "Days of data to sample when estimating volume")
+	dropRulesCmd.Flags().IntVar(&dropRuleEventTypes, "event-types", defaults.MaxEventTypes, "Number of noisiest event types to inspect for large attributes")
+	dropRulesCmd.Flags().IntVar(&dropRuleAttributes, "attributes", defaults.MaxAttributes, "Maximum attribute drop proposals per event type")
+	dropRulesCmd.Flags().Float64Var(&dropRuleMinGB, "min-gb", defaults.MinGBPerMonth, "Skip proposals below this estimated GB/month")
+	dropRulesCmd.Flags().StringVar(&dropRuleAccount, "account", "", "Only propose and apply rules for this account (default for --apply: newrelic.account_id)")
+	dropRulesCmd.Flags().StringSliceVar(&dropRuleIDs, "rule", nil, "Only apply the proposals with these IDs from the dry-run list (repeatable, default all)")
+	dropRulesCmd.Flags().BoolVar(&dropRulesYes, "yes", false, "Create the drop rules without asking for confirmation")
+
+	recommendCmd.AddCommand(rulesCmd)
+	recommendCmd.AddCommand(dropRulesCmd)
+	rootCmd.AddCommand(recommendCmd)
+}
+
+func executeRecommend() error {
+	if recommendMinConfidence != "" && !recommend.ValidConfidence(recommendMinConfidence) {
+		return fmt.Errorf("invalid --min-confidence %q (use low, medium or high)", recommendMinConfidence)
+	}
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+
+	providerName := strings.ToLower(viper.GetString("provider"))
+	filter := recommend.Filter{Rules: recommendRules, MinSavings: recommendMinSavings, MinConfidence: recommendMinConfidence}
+	rules, err := filter.SelectRules(recommend.Rules(providerName))
+	if err != nil {
+		return fmt.Errorf("%w for provider %s", err, providerName)
+	}
+	if len(rules) == 0 {
+		slog.Warn("no recommendation rules registered for provider", "provider", providerName)
+	}
+
+	recs, failed := recommend.Run(providerName, costProvider, rules)
+	if failed > 0 && failed == len(rules) {
+		return fmt.Errorf("all %d recommendation rules failed", failed)
+	}
+
+	recs = filter.Apply(recommend.Dedupe(recs))
+	recommend.Sort(recs)
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	result := &reports.Recommendations{Provider: costProvider.GetName(), Recommendations: recs}
+	return result.Output(format, outputFile)
+}
+
+// writeRecommendationRules lists the rules in the order they are evaluated
+func writeRecommendationRules(w io.Writer, rules []recommend.Rule) {
+	if len(rules) == 0 {
+		fmt.Fprintln(w, "No recommendation rules registered")
+		return
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(w, "%-30s %-10s %s\n", rule.ID, rule.Provider, rule.Description)
+	}
+}
+
+func executeDropRules(in io.Reader, w io.Writer) error {
+	provider, err := newrelic.NewProvider()
+	if err != nil {
+		return fmt.Errorf("error initializing NewRelic provider: %w", err)
+	}
+
+	opts := newrelic.DefaultDropRuleOptions()
+	opts.LookbackDays = dropRuleLookback
+	opts.MaxEventTypes = dropRuleEventTypes
+	opts.MaxAttributes = dropRuleAttributes
+	opts.MinGBPerMonth = dropRuleMinGB
+	if levels := viper.GetStringSlice("newrelic.drop_rules.noisy_log_levels"); len(levels) > 0 {
+		opts.NoisyLogLevels = levels
+	}
+	if protected := viper.GetStringSlice("newrelic.drop_rules.protected_attributes"); len(protected) > 0 {
+		opts.ProtectedAttributes = protected
+	}
+
+	proposals, err := provider.ProposeDropRules(opts)
+	if err != nil {
+		return err
+	}
+
+	account := dropRuleAccount
+	if applyDropRules && account == "" {
+		account = strings.TrimSpace(viper.GetString("newrelic.account_id"))
+		if account == "" || account == "YOUR_ACCOUNT_ID" {
+			return fmt.Errorf("--apply needs --account or newrelic.account_id to choose the account to create drop rules in")
+		}
+	}
+	if account != "" {
+		proposals = accountDropRuleProposals(proposals, account)
+	}
+
+	if !applyDropRules {
+		return writeDropRuleProposals(w, proposals)
+	}
+
+	proposals, err = selectDropRuleProposals(proposals, dropRuleIDs)
+	if err != nil {
+		return err
+	}
+	if len(proposals) == 0 {
+		fmt.Fprintf(w, "No drop rule proposals for account %s\n", account)
+		return nil
+	}
+	if !dropRulesYes {
+		confirmed, err := confirmDropRules(in, w, account, proposals)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(w, "No drop rules created")
+			return nil
+		}
+	}
+
+	results, err := provider.ApplyDropRules(proposals)
+	if err != nil {
+		return err
+	}
+	return writeDropRuleResults(w, results)
+}
+
+// accountDropRuleProposals keeps the proposals for one account
+func accountDropRuleProposals(proposals []newrelic.DropRuleProposal, account string) []newrelic.DropRuleProposal {
+	var kept []newrelic.DropRuleProposal
+	for _, proposal := range proposals {
+		if proposal.AccountID == account {
+			kept = append(kept, proposal)
+		}
+	}
+	return kept
+}
+
+// selectDropRuleProposals picks proposals by their ID in the dry-run list, or returns
+// them all when no IDs are given. An ID that is no longer proposed is an error rather
+// than skipped, so a changed proposal is never applied in its place.
+func selectDropRuleProposals(proposals []newrelic.DropRuleProposal, ids []string) ([]newrelic.DropRuleProposal, error) {
+	if len(ids) == 0 {
+		return proposals, nil
+	}
+	byID := make(map[string]newrelic.DropRuleProposal, len(proposals))
+	for _, proposal := range proposals {
+		byID[proposal.ID] = proposal
+	}
+	selected := make([]newrelic.DropRuleProposal, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		proposal, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("no drop rule proposal %s in this account; run the dry run again to see the current IDs", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, proposal)
+		}
+	}
+	return selected, nil
+}
+
+// confirmDropRules prints the exact rules about to be created and asks for a yes
+func confirmDropRules(in io.Reader, w io.Writer, account string, proposals []newrelic.DropRuleProposal) (bool, error) {
+	fmt.Fprintf(w, "About to create %d drop rules in account %s:\n", len(proposals), account)
+	for _, proposal := range proposals {
+		fmt.Fprintf(w, "  %s  %-15s  %s\n", proposal.ID, proposal.Action, proposal.NRQL)
+	}
+	fmt.Fprint(w, "Dropped data cannot be recovered. Create them? [y/N] ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("error reading confirmation: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// writeDropRuleProposals prints the dry-run view of the proposals
+func writeDropRuleProposals(w io.Writer, proposals []newrelic.DropRuleProposal) error {
+	if viper.GetString("output") == "json" {
+		return reports.WriteJSON(w, proposals)
+	}
+
+	if len(proposals) == 0 {
+		fmt.Fprintln(w, "No drop rule candidates found above the configured threshold")
+		return nil
+	}
+
+	var totalGB, totalSavings float64
+	fmt.Fprintln(w, "Proposed NRQL Drop Rules (dry run, use --apply to create them):")
+	fmt.Fprintf(w, "%-8s | %-12s | %-15s | %-10s | %-10s | %s\n", "ID", "ACCOUNT", "ACTION", "GB/MONTH", "SAVINGS", "NRQL")
+	fmt.Fprintln(w, "---------+--------------+-----------------+------------+------------+--------------------------------")
+	for _, proposal := range proposals {
+		fmt.Fprintf(w, "%-8s | %-12s | %-15s | %-10.2f | $%-9.2f | %s\n",
+			proposal.ID, proposal.AccountID, proposal.Action, proposal.GBPerMonth, proposal.MonthlySavings, proposal.NRQL)
+		totalGB += proposal.GBPerMonth
+		totalSavings += proposal.MonthlySavings
+	}
+	fmt.Fprintln(w, "---------+--------------+-----------------+------------+------------+--------------------------------")
+	fmt.Fprintf(w, "%-8s | %-12s | %-15s | %-10.2f | $%-9.2f |\n\n", "", "TOTAL", "", totalGB, totalSavings)
+
+	fmt.Fprintln(w, "NerdGraph mutations:")
+	for _, proposal := range proposals {
+		fmt.Fprintf(w, "\n# %s. %s\n%s\n", proposal.ID, proposal.Description, proposal.Mutation())
+	}
+
+	return nil
+}
+
+// writeDropRuleResults prints the outcome of applying the proposals
+func writeDropRuleResults(w io.Writer, results []newrelic.DropRuleResult) error {
+	if viper.GetString("output") == "json" {
+		return reports.WriteJSON(w, results)
+	}
+
+	failures := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failures++
+			fmt.Fprintf(w, "FAILED  account %s: %s (%s)\n", result.Proposal.AccountID, result.Proposal.NRQL, result.Error)
+			continue
+		}
+		fmt.Fprintf(w, "CREATED account %s: rule %s: %s\n", result.Proposal.AccountID, result.RuleID, result.Proposal.NRQL)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d drop rules could not be created", failures, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reconcile"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	reconcileStartDate string
+	reconcileEndDate   string
+	reconcileAll       bool
+)
+
+func init() {
+	reconcileCmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compare billed cost with the cost expected from usage",
+		Long: `Compute the cost expected from usage and the pricing catalog for each service and
+day, compare it with the billed cost and list the discrepancies above the tolerance:
+
+  over-billed    billed above expected: a price increase, or usage that is not collected
+  under-billed   billed below expected: a discount, credit or free tier
+  not-estimated  billed without usage priced in the catalog
+  not-billed     usage priced in the catalog without billed cost
+
+A difference is a discrepancy when it is larger than --tolerance percent of the
+expected cost and larger than --min-delta. Billed cost is always read from the
+billing API, whatever the provider's cost_source. Services whose cost the provider
+itself estimates, such as New Relic licenses, are left out.`,
+		Example: `  observability-cost-center reconcile --provider aws --start-date 2026-09-01 --end-date 2026-10-01
+  observability-cost-center reconcile --provider aws --tolerance 2 --all --output csv --output-file reconcile.csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeReconcile(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reconciling cost: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	defaults := reconcile.DefaultOptions()
+	reconcileCmd.Flags().StringVar(&reconcileStartDate, "start-date", time.Now().AddDate(0, 0, -30).Format("2006-01-02"), "First day to reconcile (YYYY-MM-DD)")
+	reconcileCmd.Flags().StringVar(&reconcileEndDate, "end-date", time.Now().Format("2006-01-02"), "End of the period to reconcile (YYYY-MM-DD, exclusive)")
+	reconcileCmd.Flags().Float64("tolerance", defaults.Tolerance*100, "Percent of the expected cost a difference may reach before it is a discrepancy")
+	reconcileCmd.Flags().Float64("min-delta", defaults.MinDelta, "Ignore differences smaller than this amount")
+	reconcileCmd.Flags().BoolVar(&reconcileAll, "all", false, "List every service and day in table and markdown output, not only discrepancies")
+	reconcileCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("reconcile.tolerance", reconcileCmd.Flags().Lookup("tolerance"))
+	viper.BindPFlag("reconcile.min_delta", reconcileCmd.Flags().Lookup("min-delta"))
+
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+func executeReconcile() error {
+	start, end, err := parseReportPeriod(reconcileStartDate, reconcileEndDate)
+	if err != nil {
+		return err
+	}
+	opts := reconcile.Options{
+		Tolerance: viper.GetFloat64("reconcile.tolerance") / 100,
+		MinDelta:  viper.GetFloat64("reconcile.min_delta"),
+	}
+	if opts.Tolerance < 0 || opts.MinDelta < 0 {
+		return fmt.Errorf("tolerance and min-delta must not be negative")
+	}
+
+	providerName := viper.GetString("provider")
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+	estimator, ok := costProvider.(providers.CostEstimator)
+	if !ok {
+		return fmt.Errorf("provider %s cannot estimate cost from usage", providerName)
+	}
+
+	// Providers take inclusive end dates; only billed cost is compared with the estimate
+	last := end.AddDate(0, 0, -1)
+	viper.Set(providerName+".cost_source", providers.CostSourceBilling)
+	billed, err := costProvider.GetCostData(start, last)
+	if err != nil {
+		return fmt.Errorf("error getting billed cost: %w", err)
+	}
+	expected, err := estimator.EstimateCostData(start, last)
+	if err != nil {
+		return fmt.Errorf("error estimating cost from usage: %w", err)
+	}
+
+	lines := reconcile.Reconcile(expected, billed, opts)
+	summary := reconcile.Summarize(lines)
+	slog.Info("reconciled cost", "lines", summary.Lines, "discrepancies", summary.Discrepancies)
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	result := &reports.Reconciliation{
+		Provider:  providerName,
+		StartDate: start,
+		EndDate:   end,
+		Options:   opts,
+		Lines:     lines,
+		All:       reconcileAll,
+	}
+	return result.Output(format, outputFile)
+}
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ilhicas/observability-cost-center/internal/providers"
@@ -27,7 +30,8 @@ func init() {
 		Run: func(cmd *cobra.Command, args []string) {
 			err := executeReport(cmd, args)
 			if err != nil {
-				fmt.Printf("Error executing report: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error executing report: %v\n", err)
+				os.Exit(1)
 			}
 		},
 	}
@@ -36,74 +40,45 @@ func init() {
 	reportCmd.Flags().StringVar(&endDate, "end-date", time.Now().Format("2006-01-02"), "End date for the report (YYYY-MM-DD)")
 	reportCmd.Flags().StringVar(&reportType, "type", "full", "Report type: usage, cost, or full")
 	reportCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+	reportCmd.Flags().String("textfile-dir", "", "Write OpenMetrics to this node_exporter textfile collector directory instead of stdout")
+	reportCmd.Flags().String("template", "", "Render the report with a text/template or html/template file, or a built-in template ("+strings.Join(reports.BuiltinTemplates(), ", ")+")")
+	reportCmd.Flags().Bool("collapse-details", false, "Collapse detail tables into <details> blocks (markdown output)")
+	reportCmd.Flags().Bool("anomalies", false, "Add a section with cost and usage anomalies found within the report period")
 
 	// Bind the flags to viper
 	viper.BindPFlag("output.file", reportCmd.Flags().Lookup("output-file"))
+	viper.BindPFlag("output.textfile_dir", reportCmd.Flags().Lookup("textfile-dir"))
+	viper.BindPFlag("output.template", reportCmd.Flags().Lookup("template"))
+	viper.BindPFlag("markdown.collapse_details", reportCmd.Flags().Lookup("collapse-details"))
+	viper.BindPFlag("anomalies.enabled", reportCmd.Flags().Lookup("anomalies"))
 
 	rootCmd.AddCommand(reportCmd)
 }
 
 func executeReport(cmd *cobra.Command, args []string) error {
-	// Debug information about config file
-	fmt.Printf("Using configuration file: %s\n", viper.ConfigFileUsed())
+	slog.Debug("executing report", "config", viper.ConfigFileUsed(), "awsRegion", viper.GetString("aws.region"))
 
-	// Print AWS region from config
-	fmt.Printf("AWS region from config: %s\n", viper.GetString("aws.region"))
-
-	provider := viper.GetString("provider")
-	if provider == "" {
-		return fmt.Errorf("provider is required. Use --provider flag or set in config")
-	}
-
-	start, err := time.Parse("2006-01-02", startDate)
+	start, end, err := parseReportPeriod(startDate, endDate)
 	if err != nil {
-		return fmt.Errorf("error parsing start date: %w", err)
+		return err
 	}
 
-	end, err := time.Parse("2006-01-02", endDate)
+	costProvider, err := newConfiguredProvider()
 	if err != nil {
-		return fmt.Errorf("error parsing end date: %w", err)
+		return err
 	}
 
-	var costProvider providers.Provider
-
-	// Initialize the appropriate provider
-	if provider == "aws" {
-		// Make sure we're passing the viper config to our provider
-		awsProvider, err := aws.NewCloudWatchProvider(viper.GetViper())
-		if err != nil {
-			return fmt.Errorf("error initializing AWS CloudWatch provider: %w", err)
-		}
-		costProvider = awsProvider
-	} else if provider == "newrelic" {
-		p, err := newrelic.NewProvider()
-		if err != nil {
-			return fmt.Errorf("error initializing NewRelic provider: %w", err)
-		}
-		costProvider = p
-	} else {
-		return fmt.Errorf("unsupported provider: %s", provider)
+	reportTypeEnum, err := parseReportType(reportType)
+	if err != nil {
+		return err
 	}
 
 	generator := reports.NewReportGenerator(costProvider)
-	var report *reports.Report
-	var reportTypeEnum reports.ReportType
-
-	switch reportType {
-	case "usage":
-		reportTypeEnum = reports.UsageReport
-	case "cost":
-		reportTypeEnum = reports.CostReport
-	case "full":
-		reportTypeEnum = reports.FullReport
-	default:
-		return fmt.Errorf("unsupported report type: %s", reportType)
-	}
-
-	report, err = generator.GenerateReport(reportTypeEnum, start, end)
+	report, err := generator.GenerateReport(reportTypeEnum, start, end)
 	if err != nil {
 		return fmt.Errorf("error generating report: %w", err)
 	}
+	report.ProviderKey = viper.GetString("provider")
 
 	// Only add license usage report if we actually have data
 	if costProvider.GetName() == "newrelic" && len(report.UsageData) > 0 {
@@ -115,28 +90,62 @@ func executeReport(cmd *cobra.Command, args []string) error {
 
 		// Cast to NewRelic provider to access license report methods
 		if nrProvider, ok := costProvider.(*newrelic.NewRelicProvider); ok {
-			fmt.Println("Generating NewRelic license usage report...")
+			slog.Info("generating NewRelic license usage report")
 			licenseReport, err := nrProvider.GetLicenseUsageReport(inactiveDays)
 			if err != nil {
-				fmt.Printf("Warning: Error generating license details: %v\n", err)
-			} else if licenseReport != "" {
-				// Only add license report if it's not empty
-				report.AppendCustomSection("License Usage Details", licenseReport)
-				fmt.Println("License usage report generated successfully")
+				slog.Warn("error generating license details", "error", err)
+			} else {
+				// Empty sections are skipped by AddSection
+				report.AddSection(licenseReport)
+				slog.Debug("license usage report generated")
 			}
+
+			// Attribute query CCUs to the users and dashboards that drove them
+			runawayPct := viper.GetFloat64("newrelic.compute.runaway_pct")
+			if runawayPct == 0 {
+				runawayPct = 25
+			}
+			computeReport, err := nrProvider.GetComputeAttributionReport(start, end, viper.GetInt("newrelic.compute.top_consumers"), runawayPct)
+			if err != nil {
+				slog.Warn("error generating compute attribution", "error", err)
+			} else {
+				report.AddSection(computeReport)
+			}
+
+			// Audit retention settings only when a retention policy is configured
+			if policy, ok := newrelic.RetentionPolicyFromConfig(viper.GetViper()); ok {
+				retentionReport, err := nrProvider.GetRetentionAuditReport(policy)
+				if err != nil {
+					slog.Warn("error auditing data retention", "error", err)
+				} else {
+					report.AddSection(retentionReport)
+				}
+			}
+		}
+	}
+
+	// Anomalies are detected on the report's own period, so its first window is history only
+	if viper.GetBool("anomalies.enabled") {
+		section, err := anomalySection(report, anomalyOptionsFromConfig())
+		if err != nil {
+			slog.Warn("error detecting anomalies", "error", err)
+		} else {
+			report.AddSection(section)
 		}
 	}
 
 	// Add debug information to help diagnose issues
-	fmt.Printf("Generated report with %d usage data entries and %d cost data entries\n",
-		len(report.UsageData), len(report.CostData))
+	slog.Info("generated report", "usageEntries", len(report.UsageData), "costEntries", len(report.CostData))
 
-	// Get output format from config or default to table
-	outputFormat := viper.GetString("output.format")
+	// Get output format from the --output flag or config, defaulting to table
+	outputFormat := viper.GetString("output")
+	if outputFormat == "" {
+		outputFormat = viper.GetString("output.format")
+	}
 	if outputFormat == "" {
 		outputFormat = "table"
 	}
-	fmt.Printf("Using output format: %s\n", outputFormat)
+	slog.Debug("using output format", "format", outputFormat)
 
 	// Get output file path from command line flag or config
 	outputFilePath := outputFile
@@ -144,9 +153,24 @@ func executeReport(cmd *cobra.Command, args []string) error {
 		outputFilePath = viper.GetString("output.file")
 	}
 	if outputFilePath != "" {
-		fmt.Printf("Writing output to file: %s\n", outputFilePath)
+		slog.Debug("writing output to file", "path", outputFilePath)
 	} else {
-		fmt.Println("Writing output to stdout")
+		slog.Debug("writing output to stdout")
+	}
+
+	report.Options = reports.OutputOptions{
+		CollapseDetails: viper.GetBool("markdown.collapse_details"),
+		Template:        viper.GetString("output.template"),
+	}
+
+	// The textfile collector mode always writes OpenMetrics, atomically, to its own file
+	if textfileDir := viper.GetString("output.textfile_dir"); textfileDir != "" {
+		path, err := report.OutputOpenMetricsTextfile(textfileDir)
+		if err != nil {
+			return fmt.Errorf("error writing textfile: %w", err)
+		}
+		slog.Info("wrote OpenMetrics textfile", "path", path)
+		return nil
 	}
 
 	err = report.Output(outputFormat, outputFilePath)
@@ -156,3 +180,62 @@ func executeReport(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// newConfiguredProvider initializes the provider selected with --provider or in the config
+func newConfiguredProvider() (providers.Provider, error) {
+	provider := viper.GetString("provider")
+	if provider == "" {
+		return nil, fmt.Errorf("provider is required. Use --provider flag or set in config")
+	}
+	return newProvider(provider)
+}
+
+// newProvider initializes the named provider from the config
+func newProvider(provider string) (providers.Provider, error) {
+	switch provider {
+	case "aws":
+		// Make sure we're passing the viper config to our provider
+		awsProvider, err := aws.NewCloudWatchProvider(viper.GetViper())
+		if err != nil {
+			return nil, fmt.Errorf("error initializing AWS CloudWatch provider: %w", err)
+		}
+		return awsProvider, nil
+	case "newrelic":
+		p, err := newrelic.NewProvider()
+		if err != nil {
+			return nil, fmt.Errorf("error initializing NewRelic provider: %w", err)
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
+	}
+}
+
+// parseReportPeriod parses the YYYY-MM-DD start and end dates of a report
+func parseReportPeriod(startDate, endDate string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error parsing start date: %w", err)
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error parsing end date: %w", err)
+	}
+
+	return start, end, nil
+}
+
+// parseReportType maps the --type flag to a report type
+func parseReportType(reportType string) (reports.ReportType, error) {
+	switch reportType {
+	case "usage":
+		return reports.UsageReport, nil
+	case "cost":
+		return reports.CostReport, nil
+	case "full":
+		return reports.FullReport, nil
+	default:
+		return "", fmt.Errorf("unsupported report type: %s", reportType)
+	}
+}
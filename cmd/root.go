@@ -2,13 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
+	"github.com/ilhicas/observability-cost-center/internal/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile   string
+	verbose   bool
+	quiet     bool
+	logFormat string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "observability-cost-center",
@@ -27,19 +34,28 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "observability-cost-center.yaml", "config file (default is $HOME/.observability-cost-center.yaml)")
 	rootCmd.PersistentFlags().StringP("provider", "p", "", "Provider to use (aws, newrelic)")
-	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format (json, csv, table)")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format (table, summary, json, csv, markdown, html, xlsx, parquet, openmetrics)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log debug details to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log errors to stderr")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "Log format (text, json)")
 
 	viper.BindPFlag("provider", rootCmd.PersistentFlags().Lookup("provider"))
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
 }
 
 func initConfig() {
+	// Set up logging first so everything below goes to stderr, not the report output
+	if err := logging.Setup(logging.Options{Verbose: verbose, Quiet: quiet, Format: logFormat}); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			fmt.Println("Error finding home directory:", err)
+			slog.Error("error finding home directory", "error", err)
 			os.Exit(1)
 		}
 
@@ -52,11 +68,8 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		slog.Debug("using config file", "path", viper.ConfigFileUsed())
 	} else {
-		fmt.Println("Warning: Could not read config file:", err)
+		slog.Warn("could not read config file", "error", err)
 	}
-
-	// Debug output to verify loaded configuration
-	fmt.Println("AWS Region from config:", viper.GetString("aws.region"))
 }
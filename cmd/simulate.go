@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/ilhicas/observability-cost-center/internal/simulate"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	simulateCmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Project the monthly cost delta of what-if scenarios",
+		Long: `Apply what-if scenarios to the current usage and pricing of the providers and show
+the projected monthly cost delta of each scenario side by side.
+
+Scenarios are declared under simulate.scenarios in the configuration, or in a
+standalone --scenarios file. The types are:
+
+  retention  change the retention of AWS log groups or New Relic namespaces (days)
+  drop       drop a percentage of the data of log groups or New Relic event types
+  downgrade  move New Relic users from one license type to another (from, to, count)
+  log_class  move AWS log groups to the Infrequent Access or Standard log class
+
+Resources are selected with the account and match patterns, where * matches any run
+of characters. The baseline is the last 30 days of ingest and the current retention,
+storage, classes and license counts, valued at list prices that can be overridden
+under aws.pricing and newrelic.pricing.`,
+		Example: `  observability-cost-center simulate --provider aws
+  observability-cost-center simulate --scenarios scenarios.yaml --output markdown`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeSimulate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error simulating scenarios: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	simulateCmd.Flags().String("scenarios", "", "YAML file with the scenarios (default: simulate.scenarios in the config)")
+	simulateCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("simulate.scenarios_file", simulateCmd.Flags().Lookup("scenarios"))
+
+	rootCmd.AddCommand(simulateCmd)
+}
+
+func executeSimulate() error {
+	var scenarios []simulate.Scenario
+	var err error
+	if path := viper.GetString("simulate.scenarios_file"); path != "" {
+		scenarios, err = simulate.LoadScenariosFile(path, viper.GetString("provider"))
+	} else {
+		scenarios, err = simulate.ScenariosFromViper(viper.GetViper(), viper.GetString("provider"))
+	}
+	if err != nil {
+		return err
+	}
+	if len(scenarios) == 0 {
+		return fmt.Errorf("no scenarios configured; declare them under simulate.scenarios or pass --scenarios")
+	}
+
+	// Fetch each provider's baseline once; scenarios of a provider without one report the error
+	baselines := make(map[string]*simulate.Baseline)
+	failures := make(map[string]error)
+	for _, name := range simulate.Providers(scenarios) {
+		baselines[name], failures[name] = simulationBaseline(name)
+		if failures[name] != nil {
+			slog.Warn("error fetching simulation baseline", "provider", name, "error", failures[name])
+		}
+	}
+
+	result := &reports.Simulation{}
+	evaluated := 0
+	for _, scenario := range scenarios {
+		if err := failures[scenario.Provider]; err != nil {
+			result.Results = append(result.Results, simulate.Result{Scenario: scenario, Error: err.Error()})
+			continue
+		}
+		result.Results = append(result.Results, simulate.Apply(scenario, baselines[scenario.Provider]))
+		evaluated++
+	}
+	if evaluated == 0 {
+		return fmt.Errorf("no scenario could be evaluated: %v", failures[scenarios[0].Provider])
+	}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return result.Output(format, outputFile)
+}
+
+// simulationBaseline fetches the current usage and pricing of the named provider
+func simulationBaseline(name string) (*simulate.Baseline, error) {
+	costProvider, err := newProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	baselineProvider, ok := costProvider.(simulate.BaselineProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support simulation", name)
+	}
+	return baselineProvider.SimulationBaseline()
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/providers/newrelic"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"github.com/ilhicas/observability-cost-center/internal/unitcost"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	unitCostsPeriod  string
+	unitCostsPeriods int
+)
+
+func init() {
+	unitCostsCmd := &cobra.Command{
+		Use:   "unit-costs",
+		Short: "Compute unit economics KPIs such as cost per GB or per active user",
+		Long: `Fetch the cost and usage of consecutive periods and derive unit economics KPIs by
+dividing the cost of a service by its usage over the same period: cost per GB of logs
+ingested, per metric or per active New Relic user. KPIs can also divide cost by
+business volumes, such as requests served or customers, read from a CSV file with
+period, metric and value columns.
+
+KPIs are configured under unit_economics.kpis; without configuration the built-in
+KPIs of the provider are computed. The table shows each KPI for every period, oldest
+first, and its change over the last period.`,
+		Example: `  observability-cost-center unit-costs --provider aws
+  observability-cost-center unit-costs --period 2026-09 --periods 6 --business-csv business.csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := executeUnitCosts(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error computing unit costs: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	unitCostsCmd.Flags().StringVar(&unitCostsPeriod, "period", time.Now().AddDate(0, -1, 0).Format("2006-01"), "Last period to compute (YYYY-MM, YYYY-MM-DD..YYYY-MM-DD or last-<N>d)")
+	unitCostsCmd.Flags().IntVar(&unitCostsPeriods, "periods", 3, "Number of consecutive periods, ending with --period, to show the trend over")
+	unitCostsCmd.Flags().String("business-csv", "", "CSV file of business volumes (period,metric,value) for business KPIs")
+	unitCostsCmd.Flags().StringVar(&outputFile, "output-file", "", "Output file path. If not provided, outputs to stdout")
+
+	viper.BindPFlag("unit_economics.business_csv", unitCostsCmd.Flags().Lookup("business-csv"))
+
+	rootCmd.AddCommand(unitCostsCmd)
+}
+
+func executeUnitCosts() error {
+	if unitCostsPeriods <= 0 {
+		return fmt.Errorf("periods must be positive")
+	}
+	last, err := reports.ParsePeriod(unitCostsPeriod, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	periods := make([]reports.Period, unitCostsPeriods)
+	periods[len(periods)-1] = last
+	for i := len(periods) - 2; i >= 0; i-- {
+		periods[i] = periods[i+1].Previous()
+	}
+
+	kpis, err := unitcost.KPIsFromViper(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	business := unitcost.Business{}
+	if path := viper.GetString("unit_economics.business_csv"); path != "" {
+		if business, err = unitcost.LoadBusinessCSV(path); err != nil {
+			return err
+		}
+	}
+
+	costProvider, err := newConfiguredProvider()
+	if err != nil {
+		return err
+	}
+	providerName := viper.GetString("provider")
+
+	// Active users come from the license data, which is a snapshot of today, so they
+	// only count towards the period that contains today
+	now := time.Now().UTC()
+	var activeUsers []providers.UsageData
+	if nrProvider, ok := costProvider.(*newrelic.NewRelicProvider); ok {
+		activeUsers, err = activeUserUsage(nrProvider)
+		if err != nil {
+			slog.Warn("error counting active users", "error", err)
+		}
+	}
+
+	result := &reports.UnitEconomics{Provider: costProvider.GetName(), Periods: periods}
+	generator := reports.NewReportGenerator(costProvider)
+	for _, period := range periods {
+		report, err := generator.GenerateReport(reports.FullReport, period.Start, period.LastDay())
+		if err != nil {
+			return fmt.Errorf("error generating report for %s: %w", period, err)
+		}
+		slog.Info("computing unit costs", "period", period.String(), "costEntries", len(report.CostData), "usageEntries", len(report.UsageData))
+
+		usage := report.UsageData
+		if !now.Before(period.Start) && now.Before(period.End) {
+			usage = append(usage, activeUsers...)
+		}
+		result.Values = append(result.Values, unitcost.Compute(kpis, providerName, report.CostData, usage, business, period.Start, period.End))
+	}
+
+	format := viper.GetString("output")
+	if format == "" {
+		format = "table"
+	}
+	return result.Output(format, outputFile)
+}
+
+// activeUserUsage counts the New Relic users active in the last 30 days as usage
+func activeUserUsage(nrProvider *newrelic.NewRelicProvider) ([]providers.UsageData, error) {
+	userLicenses, err := nrProvider.GetDetailedLicenseData()
+	if err != nil {
+		return nil, err
+	}
+	active := 0
+	for _, user := range userLicenses {
+		if user.IsActive {
+			active++
+		}
+	}
+	return []providers.UsageData{{
+		Service:   "Licenses",
+		Metric:    unitcost.ActiveUsersMetric,
+		Value:     float64(active),
+		Unit:      "Users",
+		Timestamp: time.Now(),
+	}}, nil
+}
@@ -0,0 +1,201 @@
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Unallocated is the team of cost that no rule could place
+const Unallocated = "Unallocated"
+
+// Line is cost of one line item allocated to a team by a rule. Unallocated lines
+// carry the reason instead.
+type Line struct {
+	Team      string  `json:"team"`
+	Provider  string  `json:"provider"`
+	AccountID string  `json:"accountId"`
+	Service   string  `json:"service"`
+	ItemName  string  `json:"itemName"`
+	Rule      string  `json:"rule,omitempty"`
+	Method    string  `json:"method,omitempty"`
+	Cost      float64 `json:"cost"`
+	Currency  string  `json:"currency"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// Usage is a provider's usage broken down by dimension, keyed by dimension
+type Usage map[string][]providers.UsageData
+
+// Allocate runs the rules over the cost entries of one provider and returns the
+// allocated and unallocated lines, merged per team, line item and rule. The allocated
+// cost always adds up to the input cost.
+func Allocate(rules []Rule, provider string, costs []providers.CostData, usage Usage) []Line {
+	merged := make(map[Line]float64)
+	add := func(c providers.CostData, team string, r *Rule, reason string, cost float64) {
+		key := Line{
+			Team:      team,
+			Provider:  provider,
+			AccountID: c.AccountID,
+			Service:   c.Service,
+			ItemName:  c.ItemName,
+			Currency:  c.Currency,
+			Reason:    reason,
+		}
+		if r != nil {
+			key.Rule, key.Method = r.Name, r.Method()
+		}
+		merged[key] += cost
+	}
+
+	for _, c := range costs {
+		r := firstMatch(rules, provider, c)
+		if r == nil {
+			add(c, Unallocated, nil, "no matching rule", c.Cost)
+			continue
+		}
+
+		shares, remainder, reason := r.shares(c, usage)
+		for team, share := range shares {
+			add(c, team, r, "", c.Cost*share)
+		}
+		if remainder > 0 {
+			add(c, Unallocated, r, reason, c.Cost*remainder)
+		}
+	}
+
+	lines := make([]Line, 0, len(merged))
+	for line, cost := range merged {
+		line.Cost = cost
+		lines = append(lines, line)
+	}
+	sortLines(lines)
+	return lines
+}
+
+func firstMatch(rules []Rule, provider string, c providers.CostData) *Rule {
+	for i := range rules {
+		if rules[i].Matches(provider, c) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// shares returns the share of a cost entry owned by each team, the share left
+// unallocated and why
+func (r Rule) shares(c providers.CostData, usage Usage) (map[string]float64, float64, string) {
+	if r.Split == nil {
+		return map[string]float64{r.Team: 1}, 0, ""
+	}
+
+	switch r.Split.By {
+	case SplitEven:
+		shares := make(map[string]float64, len(r.Split.Teams))
+		for _, team := range r.Split.Teams {
+			shares[team] += 1 / float64(len(r.Split.Teams))
+		}
+		return shares, 0, ""
+	case SplitWeights:
+		return weightShares(r.Split.Weights)
+	default:
+		return r.usageShares(c, usage[r.Split.Dimension])
+	}
+}
+
+// usageShares splits a cost entry by the usage of each dimension value. It prefers
+// usage from the entry's account and period, and falls back to all usage of the
+// period for snapshots such as license counts.
+func (r Rule) usageShares(c providers.CostData, usage []providers.UsageData) (map[string]float64, float64, string) {
+	s := r.Split
+	var candidates, inPeriod []providers.UsageData
+	for _, u := range usage {
+		if !providers.MatchPattern(s.Service, u.Service) || !providers.MatchPattern(s.Metric, u.Metric) {
+			continue
+		}
+		if s.MatchItem && !strings.EqualFold(u.Metric, c.ItemName) {
+			continue
+		}
+		if account, ok := u.Metadata["accountId"].(string); ok && c.AccountID != "" && account != c.AccountID {
+			continue
+		}
+		candidates = append(candidates, u)
+		if inCostPeriod(u.Timestamp, c) {
+			inPeriod = append(inPeriod, u)
+		}
+	}
+	if len(inPeriod) > 0 {
+		candidates = inPeriod
+	}
+
+	byTeam := make(map[string]float64)
+	unmapped, total := 0.0, 0.0
+	for _, u := range candidates {
+		total += u.Value
+		if team, ok := r.teamFor(fmt.Sprint(dimensionValue(u, s.Dimension))); ok {
+			byTeam[team] += u.Value
+		} else {
+			unmapped += u.Value
+		}
+	}
+	if total <= 0 {
+		return nil, 1, fmt.Sprintf("no %s usage to split by", s.Dimension)
+	}
+
+	shares := make(map[string]float64, len(byTeam))
+	for team, value := range byTeam {
+		shares[team] = value / total
+	}
+	return shares, unmapped / total, fmt.Sprintf("%s usage not mapped to a team", s.Dimension)
+}
+
+func dimensionValue(u providers.UsageData, dimension string) interface{} {
+	if value, ok := u.Metadata[dimension]; ok && value != nil {
+		return value
+	}
+	return ""
+}
+
+// inCostPeriod reports whether a usage timestamp falls within the days a cost entry covers
+func inCostPeriod(t time.Time, c providers.CostData) bool {
+	if c.StartTime.IsZero() || c.EndTime.IsZero() {
+		return false
+	}
+	return !t.Before(c.StartTime) && t.Before(c.EndTime)
+}
+
+// weightShares turns weights into shares
+func weightShares(weights map[string]float64) (map[string]float64, float64, string) {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return nil, 1, "weights add up to zero"
+	}
+	shares := make(map[string]float64, len(weights))
+	for team, w := range weights {
+		shares[team] = w / total
+	}
+	return shares, 0, ""
+}
+
+// sortLines orders lines by team, with unallocated last, then by cost
+func sortLines(lines []Line) {
+	sort.Slice(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if a.Team != b.Team {
+			if a.Team == Unallocated || b.Team == Unallocated {
+				return b.Team == Unallocated
+			}
+			return a.Team < b.Team
+		}
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return fmt.Sprint(a.Provider, a.AccountID, a.Service, a.ItemName) < fmt.Sprint(b.Provider, b.AccountID, b.Service, b.ItemName)
+	})
+}
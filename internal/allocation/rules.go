@@ -0,0 +1,220 @@
+// Package allocation splits observability cost to teams with declarative rules, for
+// showback and chargeback statements. Cost that no rule can place is kept as an
+// explicit unallocated remainder rather than dropped.
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// Split methods
+const (
+	// SplitEven splits cost evenly across Teams, e.g. for shared items
+	SplitEven = "even"
+
+	// SplitWeights splits cost in proportion to fixed Weights
+	SplitWeights = "weights"
+
+	// SplitUsage splits cost in proportion to each team's usage share, broken down by
+	// Dimension (log group, app, user group or tag)
+	SplitUsage = "usage"
+)
+
+// MethodDirect is the method of cost assigned to a single team
+const MethodDirect = "direct"
+
+// Match selects cost entries. Fields are case-insensitive patterns where * matches
+// any run of characters; empty fields match everything.
+type Match struct {
+	Provider string `mapstructure:"provider"`
+	Account  string `mapstructure:"account"`
+	Service  string `mapstructure:"service"`
+	Item     string `mapstructure:"item"`
+	Region   string `mapstructure:"region"`
+}
+
+// Split describes how a rule divides cost between teams
+type Split struct {
+	By      string             `mapstructure:"by"`
+	Teams   []string           `mapstructure:"teams"`   // even
+	Weights map[string]float64 `mapstructure:"weights"` // weights: team -> weight
+
+	// Usage splits read the usage of Dimension, optionally restricted to a usage
+	// service and metric, or to the metric named like the cost item (MatchItem).
+	// Mapping maps dimension value patterns to teams; without it, the dimension value
+	// is the team, as with a "team" tag.
+	Dimension string            `mapstructure:"dimension"`
+	Service   string            `mapstructure:"service"`
+	Metric    string            `mapstructure:"metric"`
+	MatchItem bool              `mapstructure:"match_item"`
+	Mapping   map[string]string `mapstructure:"mapping"`
+}
+
+// Rule assigns the cost it matches to Team, or divides it with Split
+type Rule struct {
+	Name  string `mapstructure:"name"`
+	Match Match  `mapstructure:"match"`
+	Team  string `mapstructure:"team"`
+	Split *Split `mapstructure:"split"`
+
+	mappings []mapping // Split.Mapping, most specific pattern first
+}
+
+type mapping struct {
+	pattern string
+	team    string
+}
+
+// RulesFromViper reads allocation.rules from the configuration, or the top-level
+// rules of a standalone rules file. Rules are evaluated in order and the first
+// match wins.
+//
+//	allocation:
+//	  rules:
+//	    - name: payments-account
+//	      match: {provider: aws, account: "111111111111"}
+//	      team: payments
+//	    - name: logs-by-log-group
+//	      match: {provider: aws, service: AmazonCloudWatch}
+//	      split:
+//	        by: usage
+//	        dimension: logGroup
+//	        mapping:
+//	          /aws/lambda/payments-*: payments
+//	          /aws/lambda/search-*: search
+//	    - name: shared
+//	      split: {by: even, teams: [payments, search]}
+func RulesFromViper(config *viper.Viper) ([]Rule, error) {
+	key := "allocation.rules"
+	if !config.IsSet(key) {
+		key = "rules"
+	}
+
+	var rules []Rule
+	if err := config.UnmarshalKey(key, &rules); err != nil {
+		return nil, fmt.Errorf("error reading allocation rules: %w", err)
+	}
+
+	for i := range rules {
+		r := &rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if err := r.normalize(); err != nil {
+			return nil, fmt.Errorf("invalid allocation rule %q: %w", r.Name, err)
+		}
+	}
+
+	return rules, nil
+}
+
+// LoadRulesFile reads rules from a standalone YAML file
+func LoadRulesFile(path string) ([]Rule, error) {
+	config := viper.New()
+	config.SetConfigFile(path)
+	if err := config.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading rules file: %w", err)
+	}
+	return RulesFromViper(config)
+}
+
+// normalize validates the rule and lowercases team names, since viper lowercases
+// the team keys of weights and mappings
+func (r *Rule) normalize() error {
+	r.Team = strings.ToLower(r.Team)
+	if (r.Team == "") == (r.Split == nil) {
+		return fmt.Errorf("set exactly one of team and split")
+	}
+	if r.Split == nil {
+		return nil
+	}
+
+	s := r.Split
+	s.By = strings.ToLower(s.By)
+	switch s.By {
+	case SplitEven:
+		if len(s.Teams) == 0 {
+			return fmt.Errorf("an even split needs teams")
+		}
+		for i := range s.Teams {
+			s.Teams[i] = strings.ToLower(s.Teams[i])
+		}
+	case SplitWeights:
+		if len(s.Weights) == 0 {
+			return fmt.Errorf("a weights split needs weights")
+		}
+		for team, weight := range s.Weights {
+			if weight < 0 {
+				return fmt.Errorf("weight of %s must not be negative", team)
+			}
+		}
+	case SplitUsage:
+		if s.Dimension == "" {
+			return fmt.Errorf("a usage split needs a dimension (%s, %s, %s or %s<key>)",
+				providers.DimensionLogGroup, providers.DimensionApp, providers.DimensionUserGroup, providers.DimensionTagPrefix)
+		}
+		for pattern, team := range s.Mapping {
+			r.mappings = append(r.mappings, mapping{pattern: pattern, team: strings.ToLower(team)})
+		}
+		sort.Slice(r.mappings, func(i, j int) bool {
+			if len(r.mappings[i].pattern) != len(r.mappings[j].pattern) {
+				return len(r.mappings[i].pattern) > len(r.mappings[j].pattern)
+			}
+			return r.mappings[i].pattern < r.mappings[j].pattern
+		})
+	default:
+		return fmt.Errorf("unsupported split %q (use %s, %s or %s)", s.By, SplitEven, SplitWeights, SplitUsage)
+	}
+	return nil
+}
+
+// Method returns how the rule allocates cost
+func (r Rule) Method() string {
+	if r.Split == nil {
+		return MethodDirect
+	}
+	return r.Split.By
+}
+
+// Matches reports whether the rule applies to a cost entry of the given provider
+func (r Rule) Matches(provider string, c providers.CostData) bool {
+	return providers.MatchPattern(r.Match.Provider, provider) &&
+		providers.MatchPattern(r.Match.Account, c.AccountID) &&
+		providers.MatchPattern(r.Match.Service, c.Service) &&
+		providers.MatchPattern(r.Match.Item, c.ItemName) &&
+		providers.MatchPattern(r.Match.Region, c.Region)
+}
+
+// teamFor maps a dimension value to a team; without a mapping the value is the team
+func (r Rule) teamFor(value string) (string, bool) {
+	if len(r.mappings) == 0 {
+		return strings.ToLower(value), value != ""
+	}
+	for _, m := range r.mappings {
+		if providers.MatchPattern(m.pattern, value) {
+			return m.team, true
+		}
+	}
+	return "", false
+}
+
+// UsageDimensions returns the dimensions the rules of a provider split usage by
+func UsageDimensions(rules []Rule, provider string) []string {
+	seen := make(map[string]bool)
+	var dimensions []string
+	for _, r := range rules {
+		if r.Split == nil || r.Split.By != SplitUsage || !providers.MatchPattern(r.Match.Provider, provider) {
+			continue
+		}
+		if !seen[r.Split.Dimension] {
+			seen[r.Split.Dimension] = true
+			dimensions = append(dimensions, r.Split.Dimension)
+		}
+	}
+	return dimensions
+}
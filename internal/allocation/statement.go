@@ -0,0 +1,77 @@
+package allocation
+
+import (
+	"sort"
+	"time"
+)
+
+// Statement is a chargeback statement: the cost of a period allocated to teams
+type Statement struct {
+	Start       time.Time
+	End         time.Time // Exclusive
+	Currency    string    // Empty when the cost has several currencies
+	Total       float64
+	Unallocated float64
+	Teams       []TeamTotal
+	Lines       []Line
+}
+
+// TeamTotal is the cost allocated to one team, with its share of the total
+type TeamTotal struct {
+	Team     string             `json:"team"`
+	Cost     float64            `json:"cost"`
+	Share    float64            `json:"share"`
+	Services map[string]float64 `json:"services"` // Cost per provider service
+}
+
+// NewStatement totals the allocated lines per team. The unallocated remainder is
+// listed as a team of its own, last.
+func NewStatement(start, end time.Time, lines []Line) *Statement {
+	st := &Statement{Start: start, End: end, Lines: lines}
+	sortLines(st.Lines)
+
+	byTeam := make(map[string]*TeamTotal)
+	for i, line := range lines {
+		if i == 0 {
+			st.Currency = line.Currency
+		} else if line.Currency != st.Currency {
+			st.Currency = ""
+		}
+		st.Total += line.Cost
+		if line.Team == Unallocated {
+			st.Unallocated += line.Cost
+		}
+
+		total, ok := byTeam[line.Team]
+		if !ok {
+			total = &TeamTotal{Team: line.Team, Services: make(map[string]float64)}
+			byTeam[line.Team] = total
+		}
+		total.Cost += line.Cost
+		total.Services[line.Service] += line.Cost
+	}
+
+	for _, total := range byTeam {
+		if st.Total != 0 {
+			total.Share = total.Cost / st.Total
+		}
+		st.Teams = append(st.Teams, *total)
+	}
+	sort.Slice(st.Teams, func(i, j int) bool {
+		a, b := st.Teams[i], st.Teams[j]
+		if (a.Team == Unallocated) != (b.Team == Unallocated) {
+			return b.Team == Unallocated
+		}
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return a.Team < b.Team
+	})
+
+	return st
+}
+
+// Allocated is the cost placed with teams
+func (st *Statement) Allocated() float64 {
+	return st.Total - st.Unallocated
+}
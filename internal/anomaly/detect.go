@@ -0,0 +1,257 @@
+// Package anomaly finds days whose cost or usage breaks from recent history, such as a
+// log storm doubling the CloudWatch bill, with robust statistics that a single spike
+// cannot skew.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Detection methods
+const (
+	// MethodMAD compares each day with the median of the trailing window, scaled by
+	// the median absolute deviation (MAD)
+	MethodMAD = "mad"
+
+	// MethodSeasonal removes the day-of-week pattern of the trailing window first, so
+	// quiet weekends and busy Mondays are expected rather than anomalous
+	MethodSeasonal = "seasonal"
+)
+
+// madScale makes the MAD a consistent estimator of the standard deviation for normal data
+const madScale = 1.4826
+
+// Options configures detection
+type Options struct {
+	Method string
+
+	// Window is the number of trailing days each day is compared with. Days without a
+	// full window of history are not scored.
+	Window int
+
+	// Threshold is the robust z-score from which a day is anomalous. Scores of 1.5x
+	// the threshold are warnings and 2x are critical.
+	Threshold float64
+
+	// MinChange ignores deviations smaller than this share of the expected value, and
+	// MinCost ignores cost deviations smaller than this amount
+	MinChange float64
+	MinCost   float64
+
+	// Since skips anomalies before this day, when set
+	Since time.Time
+}
+
+// DefaultOptions returns seasonal detection over four weeks
+func DefaultOptions() Options {
+	return Options{Method: MethodSeasonal, Window: 28, Threshold: 3.5, MinChange: 0.2, MinCost: 1}
+}
+
+// Anomaly is an anomalous day of a series
+type Anomaly struct {
+	Date         time.Time
+	Kind         string
+	AccountID    string
+	Service      string
+	Metric       string
+	Unit         string
+	Expected     float64
+	Actual       float64
+	Delta        float64 // Actual - Expected
+	Score        float64 // Robust z-score, negative for drops
+	Severity     string
+	Contributors []Contributor
+}
+
+// Contributor is a sub-dimension (item) of a cost anomaly and its share of the change
+type Contributor struct {
+	Name     string
+	Expected float64
+	Actual   float64
+	Delta    float64
+	Share    float64 // Share of the anomaly's delta, 0-1
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	if o.Method != MethodMAD && o.Method != MethodSeasonal {
+		return fmt.Errorf("unsupported detection method %q (use %s or %s)", o.Method, MethodMAD, MethodSeasonal)
+	}
+	if o.Window < 7 {
+		return fmt.Errorf("window must be at least 7 days, got %d", o.Window)
+	}
+	if o.Threshold <= 0 {
+		return fmt.Errorf("threshold must be positive, got %g", o.Threshold)
+	}
+	return nil
+}
+
+// Detect scores every day of every series against its trailing window and returns
+// the anomalous days, most severe first
+func Detect(series []Series, opts Options) ([]Anomaly, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var anomalies []Anomaly
+	for _, s := range series {
+		values := forecast.Values(s.Points)
+		for t := opts.Window; t < len(values); t++ {
+			if !opts.Since.IsZero() && s.Points[t].Date.Before(opts.Since) {
+				continue
+			}
+
+			expected, scale := expectation(s.Points[t-opts.Window:t], s.Points[t].Date.Weekday(), opts.Method)
+			actual := values[t]
+			delta := actual - expected
+			score := delta / scale
+
+			if math.Abs(score) < opts.Threshold {
+				continue
+			}
+			if math.Abs(delta) < opts.MinChange*math.Abs(expected) {
+				continue
+			}
+			if s.Kind == KindCost && math.Abs(delta) < opts.MinCost {
+				continue
+			}
+
+			a := Anomaly{
+				Date:      s.Points[t].Date,
+				Kind:      s.Kind,
+				AccountID: s.AccountID,
+				Service:   s.Service,
+				Metric:    s.Metric,
+				Unit:      s.Unit,
+				Expected:  expected,
+				Actual:    actual,
+				Delta:     delta,
+				Score:     score,
+				Severity:  severity(score, opts.Threshold),
+			}
+			a.Contributors = contributors(s.Parts, t, opts, delta)
+			anomalies = append(anomalies, a)
+		}
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		si, sj := severityRank(anomalies[i].Severity), severityRank(anomalies[j].Severity)
+		if si != sj {
+			return si > sj
+		}
+		if !anomalies[i].Date.Equal(anomalies[j].Date) {
+			return anomalies[i].Date.After(anomalies[j].Date)
+		}
+		return math.Abs(anomalies[i].Score) > math.Abs(anomalies[j].Score)
+	})
+	return anomalies, nil
+}
+
+// expectation returns the expected value for a day and the scale of normal deviation,
+// both estimated from the trailing window
+func expectation(window []forecast.Point, weekday time.Weekday, method string) (float64, float64) {
+	values := forecast.Values(window)
+	level := median(values)
+
+	residuals := make([]float64, len(values))
+	expected := level
+	if method == MethodSeasonal {
+		// Weekday offsets from the window median
+		byWeekday := make(map[time.Weekday][]float64)
+		for _, p := range window {
+			byWeekday[p.Date.Weekday()] = append(byWeekday[p.Date.Weekday()], p.Value)
+		}
+		offsets := make(map[time.Weekday]float64, len(byWeekday))
+		for d, v := range byWeekday {
+			offsets[d] = median(v) - level
+		}
+
+		for i, p := range window {
+			residuals[i] = p.Value - (level + offsets[p.Date.Weekday()])
+		}
+		expected = math.Max(level+offsets[weekday], 0)
+	} else {
+		for i, v := range values {
+			residuals[i] = v - level
+		}
+	}
+
+	center := median(residuals)
+	for i := range residuals {
+		residuals[i] = math.Abs(residuals[i] - center)
+	}
+	scale := madScale * median(residuals)
+
+	// A perfectly flat window has no spread; fall back to a small share of the level so
+	// the first real change still scores high without dividing by zero
+	return expected, math.Max(scale, math.Max(0.05*math.Abs(level), 1e-9))
+}
+
+// contributors compares every part of a cost series on day t with its own median over
+// the window, and returns the parts that moved in the direction of the anomaly
+func contributors(parts map[string][]forecast.Point, t int, opts Options, delta float64) []Contributor {
+	var result []Contributor
+	for name, points := range parts {
+		expected := median(forecast.Values(points[t-opts.Window : t]))
+		actual := points[t].Value
+		change := actual - expected
+		if change == 0 || (change > 0) != (delta > 0) {
+			continue
+		}
+		result = append(result, Contributor{
+			Name:     name,
+			Expected: expected,
+			Actual:   actual,
+			Delta:    change,
+			Share:    change / delta,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return math.Abs(result[i].Delta) > math.Abs(result[j].Delta) })
+	if len(result) > 3 {
+		result = result[:3]
+	}
+	return result
+}
+
+// severity grades a score relative to the threshold
+func severity(score, threshold float64) string {
+	switch s := math.Abs(score); {
+	case s >= 2*threshold:
+		return providers.SeverityCritical
+	case s >= 1.5*threshold:
+		return providers.SeverityWarning
+	default:
+		return providers.SeverityInfo
+	}
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case providers.SeverityCritical:
+		return 2
+	case providers.SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// median returns the median of values without modifying them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,93 @@
+package anomaly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// SectionID identifies the anomaly report section
+const SectionID = "anomalies"
+
+// Section builds the report section for the anomalies found in the analyzed series
+func Section(anomalies []Anomaly, series []Series, opts Options) providers.Section {
+	section := providers.Section{ID: SectionID, Title: "Cost and Usage Anomalies", Order: 50}
+
+	counts := make(map[string]int)
+	for _, a := range anomalies {
+		counts[a.Severity]++
+	}
+	section.AddSummary("Series analyzed", len(series), providers.ColumnInteger, "")
+	section.AddSummary("Method", fmt.Sprintf("%s, %d-day window, threshold %.1f", opts.Method, opts.Window, opts.Threshold), providers.ColumnString, "")
+	section.AddSummary("Anomalies", len(anomalies), providers.ColumnInteger, "")
+	section.AddSummary("Critical", counts[providers.SeverityCritical], providers.ColumnInteger, "")
+	section.AddSummary("Warning", counts[providers.SeverityWarning], providers.ColumnInteger, "")
+
+	if len(anomalies) == 0 {
+		section.Notes = append(section.Notes, "No anomalies found.")
+		return section
+	}
+
+	table := providers.SectionTable{
+		Title: "Anomalous Days",
+		Columns: []providers.SectionColumn{
+			{Key: "date", Title: "Date", Type: providers.ColumnDate},
+			{Key: "severity", Title: "Severity", Type: providers.ColumnString},
+			{Key: "kind", Title: "Kind", Type: providers.ColumnString},
+			{Key: "account_id", Title: "Account", Type: providers.ColumnString},
+			{Key: "series", Title: "Series", Type: providers.ColumnString},
+			{Key: "expected", Title: "Expected", Type: providers.ColumnNumber},
+			{Key: "actual", Title: "Actual", Type: providers.ColumnNumber},
+			{Key: "change", Title: "Change", Type: providers.ColumnPercent},
+			{Key: "unit", Title: "Unit", Type: providers.ColumnString},
+			{Key: "score", Title: "Score", Type: providers.ColumnNumber},
+			{Key: "contributors", Title: "Contributors", Type: providers.ColumnString},
+		},
+	}
+
+	for _, a := range anomalies {
+		var change interface{}
+		if a.Expected != 0 {
+			change = a.Delta / a.Expected
+		}
+		table.AddRow(a.Date, a.Severity, a.Kind, a.AccountID, seriesName(a), a.Expected, a.Actual, change, a.Unit, a.Score, formatContributors(a.Contributors))
+
+		// Warnings and critical anomalies are also raised as findings
+		if a.Severity == providers.SeverityInfo {
+			continue
+		}
+		section.Findings = append(section.Findings, providers.Finding{
+			Severity: a.Severity,
+			Message: fmt.Sprintf("%s %s of %s in account %s on %s: %.2f %s vs %.2f expected",
+				a.Kind, direction(a.Delta), seriesName(a), a.AccountID, a.Date.Format("2006-01-02"), a.Actual, a.Unit, a.Expected),
+			Detail: formatContributors(a.Contributors),
+		})
+	}
+	section.Tables = append(section.Tables, table)
+
+	return section
+}
+
+func seriesName(a Anomaly) string {
+	if a.Metric != "" {
+		return a.Service + " " + a.Metric
+	}
+	return a.Service
+}
+
+func direction(delta float64) string {
+	if delta < 0 {
+		return "drop"
+	}
+	return "spike"
+}
+
+// formatContributors lists the items that drove an anomaly with their share of it
+func formatContributors(contributors []Contributor) string {
+	parts := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		parts = append(parts, fmt.Sprintf("%s %+.2f (%.0f%%)", c.Name, c.Delta, c.Share*100))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,123 @@
+package anomaly
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Kinds of series
+const (
+	KindCost  = "cost"
+	KindUsage = "usage"
+)
+
+// Series is the daily cost or usage of one account and service (and metric, for usage)
+type Series struct {
+	Kind      string
+	AccountID string
+	Service   string
+	Metric    string // Usage metric; empty for cost
+	Unit      string // Usage unit, or the cost currency
+	Points    []forecast.Point
+
+	// Parts breaks a cost series down by item, to find what drove an anomaly
+	Parts map[string][]forecast.Point
+}
+
+// CostSeries builds one daily cost series per account and service, broken down by item
+func CostSeries(costs []providers.CostData, start, end time.Time) []Series {
+	type key struct{ account, service string }
+	groups := make(map[key][]providers.CostData)
+	for _, c := range costs {
+		k := key{c.AccountID, c.Service}
+		groups[k] = append(groups[k], c)
+	}
+
+	series := make([]Series, 0, len(groups))
+	for k, group := range groups {
+		items := make(map[string][]providers.CostData)
+		for _, c := range group {
+			items[c.ItemName] = append(items[c.ItemName], c)
+		}
+		parts := make(map[string][]forecast.Point, len(items))
+		for item, itemCosts := range items {
+			parts[item] = forecast.DailySeries(itemCosts, start, end)
+		}
+
+		series = append(series, Series{
+			Kind:      KindCost,
+			AccountID: k.account,
+			Service:   k.service,
+			Unit:      group[0].Currency,
+			Points:    forecast.DailySeries(group, start, end),
+			Parts:     parts,
+		})
+	}
+
+	sortSeries(series)
+	return series
+}
+
+// UsageSeries builds one daily usage series per account, service, metric and unit.
+// License counts are snapshots rather than daily usage, so they are left out.
+func UsageSeries(usage []providers.UsageData, start, end time.Time) []Series {
+	type key struct{ account, service, metric, unit string }
+	groups := make(map[key][]providers.UsageData)
+	for _, u := range usage {
+		if u.Service == "Licenses" {
+			continue
+		}
+		account, _ := u.Metadata["accountId"].(string)
+		k := key{account, u.Service, u.Metric, u.Unit}
+		groups[k] = append(groups[k], u)
+	}
+
+	start = day(start)
+	days := int(day(end).Sub(start).Hours() / 24)
+	series := make([]Series, 0, len(groups))
+	for k, group := range groups {
+		points := make([]forecast.Point, days)
+		for i := range points {
+			points[i].Date = start.AddDate(0, 0, i)
+		}
+		for _, u := range group {
+			i := int(day(u.Timestamp).Sub(start).Hours() / 24)
+			if i >= 0 && i < days {
+				points[i].Value += u.Value
+			}
+		}
+
+		series = append(series, Series{
+			Kind:      KindUsage,
+			AccountID: k.account,
+			Service:   k.service,
+			Metric:    k.metric,
+			Unit:      k.unit,
+			Points:    points,
+		})
+	}
+
+	sortSeries(series)
+	return series
+}
+
+func sortSeries(series []Series) {
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i], series[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Metric < b.Metric
+	})
+}
+
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,182 @@
+// Package budget evaluates monthly spend budgets, declared in the configuration per
+// provider, account, service or team, against actual or forecast cost.
+package budget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// Bases a budget is evaluated on
+const (
+	// BasisActual compares the spend of the month to date with the budget
+	BasisActual = "actual"
+
+	// BasisForecast compares the projected month-end spend with the budget, so a
+	// breach is reported before it happens
+	BasisForecast = "forecast"
+)
+
+// Budget is a monthly spend limit on the cost matching its scope. Empty scope fields
+// match everything.
+type Budget struct {
+	Name     string  `mapstructure:"name"`
+	Provider string  `mapstructure:"provider"`
+	Account  string  `mapstructure:"account"`
+	Service  string  `mapstructure:"service"`
+	Team     string  `mapstructure:"team"`
+	Amount   float64 `mapstructure:"amount"` // Monthly amount, in the currency of the cost data
+
+	// WarnPct and CriticalPct are the percentages of the amount from which the budget
+	// is a warning and critical
+	WarnPct     float64 `mapstructure:"warn_pct"`
+	CriticalPct float64 `mapstructure:"critical_pct"`
+
+	Basis string `mapstructure:"basis"`
+}
+
+// Team owns the cost of its accounts, and of its services in any account
+type Team struct {
+	Accounts []string `mapstructure:"accounts"`
+	Services []string `mapstructure:"services"`
+}
+
+// Config is the budgets section of the configuration
+type Config struct {
+	Budgets []Budget
+	Teams   map[string]Team
+}
+
+// ConfigFromViper reads budgets and teams from the configuration and fills in the
+// default thresholds and basis.
+//
+//	teams:
+//	  payments:
+//	    accounts: ["123456789012"]
+//	    services: [AmazonCloudWatch]
+//	budgets:
+//	  - name: payments-observability
+//	    provider: aws
+//	    team: payments
+//	    amount: 5000
+//	    warn_pct: 80        # default 80
+//	    critical_pct: 100   # default 100
+//	    basis: forecast     # actual (default) or forecast
+func ConfigFromViper(config *viper.Viper, defaultProvider string) (Config, error) {
+	var cfg Config
+	if err := config.UnmarshalKey("budgets", &cfg.Budgets); err != nil {
+		return Config{}, fmt.Errorf("error reading budgets: %w", err)
+	}
+	if err := config.UnmarshalKey("teams", &cfg.Teams); err != nil {
+		return Config{}, fmt.Errorf("error reading teams: %w", err)
+	}
+
+	names := make(map[string]bool, len(cfg.Budgets))
+	for i := range cfg.Budgets {
+		b := &cfg.Budgets[i]
+		if b.Name == "" {
+			b.Name = fmt.Sprintf("budget-%d", i+1)
+		}
+		if b.Provider == "" {
+			b.Provider = defaultProvider
+		}
+		if b.WarnPct == 0 {
+			b.WarnPct = 80
+		}
+		if b.CriticalPct == 0 {
+			b.CriticalPct = 100
+		}
+		b.Basis = strings.ToLower(b.Basis)
+		if b.Basis == "" {
+			b.Basis = BasisActual
+		}
+
+		if err := b.validate(cfg.Teams); err != nil {
+			return Config{}, fmt.Errorf("invalid budget %q: %w", b.Name, err)
+		}
+		if names[b.Name] {
+			return Config{}, fmt.Errorf("duplicate budget name %q", b.Name)
+		}
+		names[b.Name] = true
+	}
+
+	return cfg, nil
+}
+
+func (b Budget) validate(teams map[string]Team) error {
+	if b.Provider == "" {
+		return fmt.Errorf("provider is required when no default provider is configured")
+	}
+	if b.Amount <= 0 {
+		return fmt.Errorf("amount must be positive, got %g", b.Amount)
+	}
+	if b.WarnPct < 0 || b.WarnPct > b.CriticalPct {
+		return fmt.Errorf("warn_pct (%g) must be between 0 and critical_pct (%g)", b.WarnPct, b.CriticalPct)
+	}
+	if b.Basis != BasisActual && b.Basis != BasisForecast {
+		return fmt.Errorf("unsupported basis %q (use %s or %s)", b.Basis, BasisActual, BasisForecast)
+	}
+	// Viper lowercases map keys, so teams are matched case-insensitively
+	if b.Team != "" {
+		if _, ok := teams[strings.ToLower(b.Team)]; !ok {
+			return fmt.Errorf("team %q is not defined under teams", b.Team)
+		}
+	}
+	return nil
+}
+
+// Scope describes what the budget covers, e.g. "account 1234, service AmazonCloudWatch"
+func (b Budget) Scope() string {
+	var parts []string
+	if b.Team != "" {
+		parts = append(parts, "team "+b.Team)
+	}
+	if b.Account != "" {
+		parts = append(parts, "account "+b.Account)
+	}
+	if b.Service != "" {
+		parts = append(parts, "service "+b.Service)
+	}
+	if len(parts) == 0 {
+		return "all cost"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Filter keeps the cost entries in the budget's scope. The provider is matched when
+// the cost is fetched.
+func (b Budget) Filter(costs []providers.CostData, teams map[string]Team) []providers.CostData {
+	team, hasTeam := teams[strings.ToLower(b.Team)]
+
+	var filtered []providers.CostData
+	for _, c := range costs {
+		if b.Account != "" && c.AccountID != b.Account {
+			continue
+		}
+		if b.Service != "" && !strings.EqualFold(c.Service, b.Service) {
+			continue
+		}
+		if hasTeam && !team.owns(c) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+func (t Team) owns(c providers.CostData) bool {
+	for _, account := range t.Accounts {
+		if c.AccountID == account {
+			return true
+		}
+	}
+	for _, service := range t.Services {
+		if strings.EqualFold(c.Service, service) {
+			return true
+		}
+	}
+	return false
+}
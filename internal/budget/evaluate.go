@@ -0,0 +1,143 @@
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Budget statuses, from best to worst. Warning and critical share the finding severities.
+const (
+	StatusOK       = "ok"
+	StatusWarning  = providers.SeverityWarning
+	StatusCritical = providers.SeverityCritical
+	StatusError    = "error" // The budget could not be evaluated
+)
+
+// Status is the evaluation of one budget for a month
+type Status struct {
+	Budget   Budget
+	Currency string
+	Month    time.Time // First day of the evaluated month
+	Actual   float64   // Spend of the month to date
+
+	// Forecast is the projected month-end spend, for forecast budgets
+	Forecast *forecast.Projection
+	Model    string // Forecast model used
+
+	Used   float64 // Share of the amount used on the budget's basis, e.g. 0.85
+	Status string
+	Error  string
+}
+
+// Value is the spend the budget is evaluated on: actual, or projected month-end
+func (s Status) Value() float64 {
+	if s.Forecast != nil {
+		return s.Forecast.Expected
+	}
+	return s.Actual
+}
+
+// Remaining is the amount left before the budget is used up, negative when over
+func (s Status) Remaining() float64 {
+	return s.Budget.Amount - s.Value()
+}
+
+// Evaluate evaluates a budget on the cost in its scope between start and asOf
+// (exclusive). Only the current month is needed for actual budgets; forecast budgets
+// need enough history before it for the forecast models.
+func Evaluate(b Budget, costs []providers.CostData, start, asOf time.Time, opts forecast.Options) Status {
+	month := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	status := Status{Budget: b, Month: month}
+	for _, c := range costs {
+		if c.Currency != "" {
+			status.Currency = c.Currency
+			break
+		}
+	}
+
+	history := forecast.DailySeries(costs, start, asOf)
+	for _, p := range history {
+		if !p.Date.Before(month) {
+			status.Actual += p.Value
+		}
+	}
+
+	if b.Basis == BasisForecast {
+		result, err := forecast.Run(history, forecast.Models(), opts)
+		if err != nil {
+			status.Status, status.Error = StatusError, err.Error()
+			return status
+		}
+		best, ok := result.Model(result.Best)
+		if !ok {
+			status.Status, status.Error = StatusError, "no forecast model had enough history"
+			return status
+		}
+		status.Forecast = &best.MonthEnd
+		status.Model = best.Model
+	}
+
+	status.Used = status.Value() / b.Amount
+	switch pct := status.Used * 100; {
+	case pct >= b.CriticalPct:
+		status.Status = StatusCritical
+	case pct >= b.WarnPct:
+		status.Status = StatusWarning
+	default:
+		status.Status = StatusOK
+	}
+	return status
+}
+
+// Breached reports whether any status is at or above the given level (warning or
+// critical). Statuses that could not be evaluated are not breaches; check Errors.
+func Breached(statuses []Status, level string) bool {
+	for _, s := range statuses {
+		if statusRank(s.Status) >= statusRank(level) && s.Status != StatusError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the budgets that could not be evaluated
+func Errors(statuses []Status) error {
+	var failed []string
+	for _, s := range statuses {
+		if s.Status == StatusError {
+			failed = append(failed, fmt.Sprintf("%s: %s", s.Budget.Name, s.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d budget(s) could not be evaluated: %s", len(failed), strings.Join(failed, "; "))
+}
+
+// Sort orders statuses worst first, then by share of the budget used
+func Sort(statuses []Status) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		ri, rj := statusRank(statuses[i].Status), statusRank(statuses[j].Status)
+		if ri != rj {
+			return ri > rj
+		}
+		return statuses[i].Used > statuses[j].Used
+	})
+}
+
+func statusRank(status string) int {
+	switch status {
+	case StatusError:
+		return 3
+	case StatusCritical:
+		return 2
+	case StatusWarning:
+		return 1
+	}
+	return 0
+}
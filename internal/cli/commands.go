@@ -43,7 +43,7 @@ func GenerateReport(providerName, reportType, startDate, endDate, outputPath, fo
 			}
 
 			// Add license report to the main report
-			report.AppendCustomSection("License Usage Details", licenseReport)
+			report.AddSection(licenseReport)
 		}
 	}
 
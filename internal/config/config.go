@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/spf13/viper"
@@ -25,8 +26,9 @@ type AWSConfig struct {
 
 // NewRelicConfig holds New Relic-specific configuration
 type NewRelicConfig struct {
-	APIKey    string `mapstructure:"api_key"`
-	AccountID string `mapstructure:"account_id"`
+	APIKey             string `mapstructure:"api_key"`
+	AccountID          string `mapstructure:"account_id"`
+	LicenseAttribution string `mapstructure:"license_attribution"`
 }
 
 // Load loads configuration from file and environment variables
@@ -69,7 +71,7 @@ func Load() (*Config, error) {
 
 	// If a config file is found, read it in
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		slog.Debug("using config file", "path", viper.ConfigFileUsed())
 	}
 
 	// Unmarshal config
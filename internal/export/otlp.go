@@ -0,0 +1,236 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Supported OTLP transports
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+)
+
+// Resource attributes identifying where the data came from
+const (
+	AttributeProvider  = "observability.provider"
+	AttributeAccountID = "observability.account.id"
+)
+
+// scopeName identifies this tool as the instrumentation scope of the exported metrics
+const scopeName = "github.com/ilhicas/observability-cost-center"
+
+// OTLPOptions configures the connection to an OpenTelemetry Collector
+type OTLPOptions struct {
+	Endpoint string            // host:port, or a full URL such as https://collector:4318/v1/metrics
+	Protocol string            // grpc (default) or http
+	Insecure bool              // Disable TLS
+	Headers  map[string]string // Sent with every export request, e.g. for authentication
+	Timeout  time.Duration
+}
+
+// NewOTLPExporter creates an OTLP metric exporter for the configured transport
+func NewOTLPExporter(ctx context.Context, opts OTLPOptions) (sdkmetric.Exporter, error) {
+	isURL := strings.Contains(opts.Endpoint, "://")
+
+	switch strings.ToLower(opts.Protocol) {
+	case "", ProtocolGRPC:
+		var options []otlpmetricgrpc.Option
+		if isURL {
+			options = append(options, otlpmetricgrpc.WithEndpointURL(opts.Endpoint))
+		} else if opts.Endpoint != "" {
+			options = append(options, otlpmetricgrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			options = append(options, otlpmetricgrpc.WithHeaders(opts.Headers))
+		}
+		if opts.Timeout > 0 {
+			options = append(options, otlpmetricgrpc.WithTimeout(opts.Timeout))
+		}
+		return otlpmetricgrpc.New(ctx, options...)
+	case ProtocolHTTP:
+		var options []otlpmetrichttp.Option
+		if isURL {
+			options = append(options, otlpmetrichttp.WithEndpointURL(opts.Endpoint))
+		} else if opts.Endpoint != "" {
+			options = append(options, otlpmetrichttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			options = append(options, otlpmetrichttp.WithHeaders(opts.Headers))
+		}
+		if opts.Timeout > 0 {
+			options = append(options, otlpmetrichttp.WithTimeout(opts.Timeout))
+		}
+		return otlpmetrichttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use grpc or http)", opts.Protocol)
+	}
+}
+
+// ExportReport sends the report's usage and cost data through the exporter. Each
+// provider/account pair is exported as its own resource, and every data point keeps
+// the timestamp of the period it describes. The exporter is not shut down.
+func ExportReport(ctx context.Context, exporter sdkmetric.Exporter, report *reports.Report) (int, error) {
+	batches := ReportMetrics(report)
+	for _, batch := range batches {
+		if err := exporter.Export(ctx, batch); err != nil {
+			return 0, fmt.Errorf("error exporting OTLP metrics: %w", err)
+		}
+	}
+
+	points := 0
+	for _, batch := range batches {
+		for _, scope := range batch.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				points += dataPointCount(m.Data)
+			}
+		}
+	}
+	slog.Debug("exported OTLP metrics", "resources", len(batches), "dataPoints", points)
+
+	return points, nil
+}
+
+// ReportMetrics converts a report into one ResourceMetrics per account:
+//
+//	observability.cost               delta sum per service, item and currency over each cost period
+//	observability.usage              gauge per service, metric and unit
+//	observability.license.utilization gauge (0-1) per license type
+func ReportMetrics(report *reports.Report) []*metricdata.ResourceMetrics {
+	type accountMetrics struct {
+		cost        []metricdata.DataPoint[float64]
+		usage       []metricdata.DataPoint[float64]
+		utilization []metricdata.DataPoint[float64]
+	}
+	accounts := make(map[string]*accountMetrics)
+	account := func(id string) *accountMetrics {
+		if accounts[id] == nil {
+			accounts[id] = &accountMetrics{}
+		}
+		return accounts[id]
+	}
+
+	for _, c := range report.CostData {
+		account(c.AccountID).cost = append(account(c.AccountID).cost, metricdata.DataPoint[float64]{
+			Attributes: attribute.NewSet(
+				attribute.String("service", c.Service),
+				attribute.String("item", c.ItemName),
+				attribute.String("currency", c.Currency),
+			),
+			StartTime: c.StartTime,
+			Time:      c.EndTime,
+			Value:     c.Cost,
+		})
+	}
+
+	for _, u := range report.UsageData {
+		accountID, _ := u.Metadata["accountId"].(string)
+		a := account(accountID)
+		a.usage = append(a.usage, metricdata.DataPoint[float64]{
+			Attributes: attribute.NewSet(
+				attribute.String("service", u.Service),
+				attribute.String("metric", u.Metric),
+				attribute.String("unit", u.Unit),
+			),
+			Time:  u.Timestamp,
+			Value: u.Value,
+		})
+
+		if u.Service == "Licenses" {
+			if pct, ok := u.Metadata["utilizationPct"].(float64); ok {
+				a.utilization = append(a.utilization, metricdata.DataPoint[float64]{
+					Attributes: attribute.NewSet(attribute.String("license_type", strings.TrimSuffix(u.Metric, " Licenses"))),
+					Time:       u.Timestamp,
+					Value:      pct / 100,
+				})
+			}
+		}
+	}
+
+	accountIDs := make([]string, 0, len(accounts))
+	for id := range accounts {
+		accountIDs = append(accountIDs, id)
+	}
+	sort.Strings(accountIDs)
+
+	batches := make([]*metricdata.ResourceMetrics, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		a := accounts[id]
+
+		attrs := []attribute.KeyValue{
+			attribute.String("service.name", "observability-cost-center"),
+			attribute.String(AttributeProvider, report.ProviderName),
+		}
+		if id != "" {
+			attrs = append(attrs, attribute.String(AttributeAccountID, id))
+		}
+
+		var metrics []metricdata.Metrics
+		if len(a.cost) > 0 {
+			metrics = append(metrics, metricdata.Metrics{
+				Name:        "observability.cost",
+				Description: "Cost incurred over each billing period",
+				Unit:        "{currency}",
+				Data: metricdata.Sum[float64]{
+					DataPoints:  a.cost,
+					Temporality: metricdata.DeltaTemporality,
+					IsMonotonic: true,
+				},
+			})
+		}
+		if len(a.usage) > 0 {
+			metrics = append(metrics, metricdata.Metrics{
+				Name:        "observability.usage",
+				Description: "Usage reported by the provider; the unit attribute holds its unit",
+				Data:        metricdata.Gauge[float64]{DataPoints: a.usage},
+			})
+		}
+		if len(a.utilization) > 0 {
+			metrics = append(metrics, metricdata.Metrics{
+				Name:        "observability.license.utilization",
+				Description: "Share of available licenses in use",
+				Unit:        "1",
+				Data:        metricdata.Gauge[float64]{DataPoints: a.utilization},
+			})
+		}
+
+		batches = append(batches, &metricdata.ResourceMetrics{
+			Resource: resource.NewSchemaless(attrs...),
+			ScopeMetrics: []metricdata.ScopeMetrics{{
+				Scope:   instrumentation.Scope{Name: scopeName},
+				Metrics: metrics,
+			}},
+		})
+	}
+
+	return batches
+}
+
+func dataPointCount(data metricdata.Aggregation) int {
+	switch d := data.(type) {
+	case metricdata.Sum[float64]:
+		return len(d.DataPoints)
+	case metricdata.Gauge[float64]:
+		return len(d.DataPoints)
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/reports"
+	colmetric "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver is an in-process OTLP/HTTP receiver that keeps the decoded requests
+type otlpReceiver struct {
+	mu       sync.Mutex
+	requests []*colmetric.ExportMetricsServiceRequest
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var export colmetric.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &export); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, &export)
+	r.mu.Unlock()
+
+	out, _ := proto.Marshal(&colmetric.ExportMetricsServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(out)
+}
+
+// resourceMetrics returns every received resource, keyed by its account attribute
+func (r *otlpReceiver) resourceMetrics() map[string]*metricpb.ResourceMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resources := make(map[string]*metricpb.ResourceMetrics)
+	for _, export := range r.requests {
+		for _, rm := range export.ResourceMetrics {
+			resources[attributeValue(rm.Resource.Attributes, AttributeAccountID)] = rm
+		}
+	}
+	return resources
+}
+
+func attributeValue(attrs []*commonpb.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+func TestExportReportToOTLPReceiver(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	report := &reports.Report{
+		ProviderName: "newrelic",
+		CostData: []providers.CostData{
+			{Service: "DataPlatform", ItemName: "GigabytesIngested", Cost: 42.5, Currency: "USD", AccountID: "1234567", StartTime: day, EndTime: day.AddDate(0, 0, 1)},
+		},
+		UsageData: []providers.UsageData{
+			{Service: "DataPlatform", Metric: "DataSize", Value: 141.7, Unit: "GB", Timestamp: day, Metadata: map[string]interface{}{"accountId": "1234567"}},
+			{Service: "Licenses", Metric: "Full platform Licenses", Value: 8, Unit: "Users", Timestamp: day, Metadata: map[string]interface{}{"utilizationPct": 80.0}},
+		},
+	}
+
+	ctx := context.Background()
+	exporter, err := NewOTLPExporter(ctx, OTLPOptions{Endpoint: server.URL + "/v1/metrics", Protocol: ProtocolHTTP, Insecure: true, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter: %v", err)
+	}
+	points, err := ExportReport(ctx, exporter, report)
+	if err != nil {
+		t.Fatalf("ExportReport: %v", err)
+	}
+	if err := exporter.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if points != 4 {
+		t.Errorf("exported %d data points, want 4", points)
+	}
+
+	resources := receiver.resourceMetrics()
+	if len(resources) != 2 {
+		t.Fatalf("received %d resources, want the account and the organization", len(resources))
+	}
+	for account, rm := range resources {
+		if got := attributeValue(rm.Resource.Attributes, AttributeProvider); got != "newrelic" {
+			t.Errorf("resource %q has provider %q, want newrelic", account, got)
+		}
+	}
+
+	values := func(rm *metricpb.ResourceMetrics) map[string]float64 {
+		got := make(map[string]float64)
+		for _, scope := range rm.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				switch {
+				case m.GetSum() != nil:
+					for _, dp := range m.GetSum().DataPoints {
+						got[m.Name] += dp.GetAsDouble()
+					}
+				case m.GetGauge() != nil:
+					for _, dp := range m.GetGauge().DataPoints {
+						got[m.Name] += dp.GetAsDouble()
+					}
+				}
+			}
+		}
+		return got
+	}
+
+	account, ok := resources["1234567"]
+	if !ok {
+		t.Fatalf("no resource for account 1234567")
+	}
+	want := map[string]float64{"observability.cost": 42.5, "observability.usage": 141.7}
+	if got := values(account); !equalValues(got, want) {
+		t.Errorf("account metrics = %v, want %v", got, want)
+	}
+
+	want = map[string]float64{"observability.usage": 8, "observability.license.utilization": 0.8}
+	if got := values(resources[""]); !equalValues(got, want) {
+		t.Errorf("organization metrics = %v, want %v", got, want)
+	}
+}
+
+func equalValues(got, want map[string]float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for name, v := range want {
+		if d := got[name] - v; d > 1e-9 || d < -1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+// Package forecast projects month-end and next-month spend from a daily cost series.
+// It only needs the cost history, so it works for every provider, including those
+// without a native forecast API.
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Options configures a forecast
+type Options struct {
+	// Confidence is the coverage of the projection bands, e.g. 0.8 for 80%
+	Confidence float64
+
+	// Backtests holds out the last BacktestDays days this many times, each time one
+	// window further back, and scores every model on the held-out days
+	Backtests    int
+	BacktestDays int
+}
+
+// DefaultOptions returns an 80% band and three one-week backtests
+func DefaultOptions() Options {
+	return Options{Confidence: 0.8, Backtests: 3, BacktestDays: 7}
+}
+
+// Projection is a projected total with its confidence band
+type Projection struct {
+	Expected float64 `json:"expected"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+}
+
+// Backtest scores a model on held-out history
+type Backtest struct {
+	Runs int     `json:"runs"` // Backtests that had enough history
+	MAE  float64 `json:"mae"`  // Mean absolute daily error
+	MAPE float64 `json:"mape"` // Mean absolute daily percentage error (0-1), over days with cost
+	Bias float64 `json:"bias"` // Mean error of the held-out total relative to the actual total, positive when over-forecasting
+}
+
+// ModelResult is the forecast of one model
+type ModelResult struct {
+	Model     string
+	MonthEnd  Projection // Spend of the current month: actual to date plus forecast
+	NextMonth Projection
+	Daily     []Point // Daily forecast through the end of next month
+	Backtest  Backtest
+	Error     string // Why the model could not be used, if it could not
+}
+
+// Result is the forecast of every model
+type Result struct {
+	AsOf        time.Time // First day without history
+	Month       time.Time // First day of the current month
+	MonthToDate float64   // Actual spend of the current month up to AsOf
+	History     []Point
+	Confidence  float64
+	Models      []ModelResult
+	Best        string // Model with the lowest backtest error
+}
+
+// Run forecasts the spend after the last day of history with every model in models
+func Run(history []Point, models []Model, opts Options) (*Result, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no cost history to forecast from")
+	}
+	if opts.Confidence <= 0 || opts.Confidence >= 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1, got %g", opts.Confidence)
+	}
+
+	asOf := history[len(history)-1].Date.AddDate(0, 0, 1)
+	month := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := month.AddDate(0, 1, 0)
+	monthAfter := month.AddDate(0, 2, 0)
+
+	result := &Result{AsOf: asOf, Month: month, History: history, Confidence: opts.Confidence}
+	for _, p := range history {
+		if !p.Date.Before(month) {
+			result.MonthToDate += p.Value
+		}
+	}
+
+	// Days left in the current month, and in the current and next month together
+	remaining := daysBetween(asOf, nextMonth)
+	horizon := daysBetween(asOf, monthAfter)
+	z := math.Sqrt2 * math.Erfinv(opts.Confidence)
+
+	bestMAE := math.Inf(1)
+	for _, model := range models {
+		mr := ModelResult{Model: model.Name()}
+		if len(history) < model.MinHistory() {
+			mr.Error = fmt.Sprintf("needs at least %d days of history, got %d", model.MinHistory(), len(history))
+			result.Models = append(result.Models, mr)
+			continue
+		}
+
+		fitted, daily := model.Fit(history, horizon)
+		sigma := residualStdDev(Values(history), fitted)
+
+		mr.Daily = make([]Point, horizon)
+		for k := range daily {
+			mr.Daily[k] = Point{Date: asOf.AddDate(0, 0, k), Value: daily[k]}
+		}
+		mr.MonthEnd = project(result.MonthToDate, daily[:remaining], sigma, z)
+		mr.NextMonth = project(0, daily[remaining:], sigma, z)
+		mr.Backtest = backtest(history, model, opts.Backtests, opts.BacktestDays)
+
+		if mr.Backtest.Runs > 0 && mr.Backtest.MAE < bestMAE {
+			bestMAE = mr.Backtest.MAE
+			result.Best = mr.Model
+		}
+		result.Models = append(result.Models, mr)
+	}
+
+	// Without backtests (short history), fall back to the first model that could run
+	if result.Best == "" {
+		for _, mr := range result.Models {
+			if mr.Error == "" {
+				result.Best = mr.Model
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Model returns the result of the named model
+func (r *Result) Model(name string) (ModelResult, bool) {
+	for _, mr := range r.Models {
+		if mr.Model == name {
+			return mr, true
+		}
+	}
+	return ModelResult{}, false
+}
+
+// project sums actual spend and the forecast days, with a band that widens with the
+// square root of the number of forecast days (independent daily errors)
+func project(actual float64, daily []float64, sigma, z float64) Projection {
+	expected := actual
+	for _, v := range daily {
+		expected += v
+	}
+	margin := z * sigma * math.Sqrt(float64(len(daily)))
+	return Projection{
+		Expected: expected,
+		Lower:    math.Max(expected-margin, actual),
+		Upper:    expected + margin,
+	}
+}
+
+// backtest fits the model on history up to each held-out window and scores the
+// forecast of that window
+func backtest(history []Point, model Model, runs, days int) Backtest {
+	var bt Backtest
+	if days <= 0 {
+		return bt
+	}
+
+	var absErr, pctErr, bias float64
+	var errDays, pctDays int
+	for run := 1; run <= runs; run++ {
+		cut := len(history) - run*days
+		if cut < model.MinHistory() {
+			break
+		}
+
+		_, predicted := model.Fit(history[:cut], days)
+		actualTotal, predictedTotal := 0.0, 0.0
+		for k, p := range history[cut : cut+days] {
+			diff := predicted[k] - p.Value
+			absErr += math.Abs(diff)
+			errDays++
+			if p.Value > 0 {
+				pctErr += math.Abs(diff) / p.Value
+				pctDays++
+			}
+			actualTotal += p.Value
+			predictedTotal += predicted[k]
+		}
+		if actualTotal > 0 {
+			bias += (predictedTotal - actualTotal) / actualTotal
+		}
+		bt.Runs++
+	}
+
+	if bt.Runs == 0 {
+		return bt
+	}
+	bt.MAE = absErr / float64(errDays)
+	if pctDays > 0 {
+		bt.MAPE = pctErr / float64(pctDays)
+	}
+	bt.Bias = bias / float64(bt.Runs)
+	return bt
+}
+
+// residualStdDev is the standard deviation of the in-sample errors
+func residualStdDev(actual, fitted []float64) float64 {
+	if len(actual) < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i := range actual {
+		diff := actual[i] - fitted[i]
+		sum += diff * diff
+	}
+	return math.Sqrt(sum / float64(len(actual)-1))
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
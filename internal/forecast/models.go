@@ -0,0 +1,193 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// Model forecasts a daily series
+type Model interface {
+	// Name identifies the model in output and in --model
+	Name() string
+
+	// MinHistory is the number of days of history the model needs
+	MinHistory() int
+
+	// Fit fits the model to history and returns its in-sample fitted values, one per
+	// history day, and the forecast for the horizon days after the last one
+	Fit(history []Point, horizon int) (fitted, forecast []float64)
+}
+
+// Names of the built-in models
+const (
+	ModelLinear               = "linear"
+	ModelExponentialSmoothing = "exponential-smoothing"
+	ModelWeekdaySeasonal      = "weekday-seasonal"
+)
+
+// Models returns the built-in models in output order
+func Models() []Model {
+	return []Model{Linear{}, ExponentialSmoothing{Damping: 0.98}, WeekdaySeasonal{}}
+}
+
+// Linear fits a least-squares trend line through the series
+type Linear struct{}
+
+func (Linear) Name() string    { return ModelLinear }
+func (Linear) MinHistory() int { return 2 }
+
+func (Linear) Fit(history []Point, horizon int) ([]float64, []float64) {
+	intercept, slope := linearFit(Values(history))
+
+	fitted := make([]float64, len(history))
+	for i := range fitted {
+		fitted[i] = intercept + slope*float64(i)
+	}
+	forecast := make([]float64, horizon)
+	for k := range forecast {
+		forecast[k] = nonNegative(intercept + slope*float64(len(history)+k))
+	}
+	return fitted, forecast
+}
+
+// ExponentialSmoothing is Holt's linear method with a damped trend, so the trend
+// flattens out over long horizons instead of running away. The smoothing factors are
+// chosen by minimizing the one-step-ahead squared error.
+type ExponentialSmoothing struct {
+	Damping float64 // 0-1, 1 keeps the trend undamped
+}
+
+func (ExponentialSmoothing) Name() string    { return ModelExponentialSmoothing }
+func (ExponentialSmoothing) MinHistory() int { return 3 }
+
+func (m ExponentialSmoothing) Fit(history []Point, horizon int) ([]float64, []float64) {
+	values := Values(history)
+
+	var best struct {
+		sse          float64
+		fitted       []float64
+		level, trend float64
+	}
+	best.sse = math.Inf(1)
+	for alpha := 0.1; alpha < 0.95; alpha += 0.1 {
+		for _, beta := range []float64{0.01, 0.05, 0.1, 0.2, 0.3} {
+			fitted, level, trend, sse := m.smooth(values, alpha, beta)
+			if sse < best.sse {
+				best.sse, best.fitted, best.level, best.trend = sse, fitted, level, trend
+			}
+		}
+	}
+
+	forecast := make([]float64, horizon)
+	damped := 0.0
+	for k := range forecast {
+		damped += math.Pow(m.Damping, float64(k+1))
+		forecast[k] = nonNegative(best.level + damped*best.trend)
+	}
+	return best.fitted, forecast
+}
+
+// smooth runs the damped Holt recursion and returns the one-step-ahead fitted values,
+// the final level and trend, and the sum of squared errors
+func (m ExponentialSmoothing) smooth(values []float64, alpha, beta float64) ([]float64, float64, float64, float64) {
+	fitted := make([]float64, len(values))
+	level, trend := values[0], 0.0
+	if len(values) > 1 {
+		trend = values[1] - values[0]
+	}
+	fitted[0] = values[0]
+
+	sse := 0.0
+	for t := 1; t < len(values); t++ {
+		fitted[t] = level + m.Damping*trend
+		err := values[t] - fitted[t]
+		sse += err * err
+
+		previous := level
+		level = alpha*values[t] + (1-alpha)*(level+m.Damping*trend)
+		trend = beta*(level-previous) + (1-beta)*m.Damping*trend
+	}
+	return fitted, level, trend, sse
+}
+
+// WeekdaySeasonal removes a day-of-week pattern (quiet weekends, busy Mondays), fits a
+// trend line through what is left and puts the pattern back
+type WeekdaySeasonal struct{}
+
+func (WeekdaySeasonal) Name() string    { return ModelWeekdaySeasonal }
+func (WeekdaySeasonal) MinHistory() int { return 14 }
+
+func (WeekdaySeasonal) Fit(history []Point, horizon int) ([]float64, []float64) {
+	factors := weekdayFactors(history)
+
+	adjusted := make([]float64, len(history))
+	for i, p := range history {
+		adjusted[i] = p.Value / factors[p.Date.Weekday()]
+	}
+	intercept, slope := linearFit(adjusted)
+
+	fitted := make([]float64, len(history))
+	for i, p := range history {
+		fitted[i] = (intercept + slope*float64(i)) * factors[p.Date.Weekday()]
+	}
+
+	last := history[len(history)-1].Date
+	forecast := make([]float64, horizon)
+	for k := range forecast {
+		weekday := last.AddDate(0, 0, k+1).Weekday()
+		forecast[k] = nonNegative((intercept + slope*float64(len(history)+k)) * factors[weekday])
+	}
+	return fitted, forecast
+}
+
+// weekdayFactors returns each weekday's average relative to the overall average. Days
+// without cost get a factor of 1 so the series can be divided by them.
+func weekdayFactors(history []Point) map[time.Weekday]float64 {
+	var sums, counts [7]float64
+	total := 0.0
+	for _, p := range history {
+		sums[p.Date.Weekday()] += p.Value
+		counts[p.Date.Weekday()]++
+		total += p.Value
+	}
+	mean := total / float64(len(history))
+
+	factors := make(map[time.Weekday]float64, 7)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		factors[d] = 1
+		if counts[d] > 0 && mean > 0 && sums[d] > 0 {
+			factors[d] = sums[d] / counts[d] / mean
+		}
+	}
+	return factors
+}
+
+// linearFit returns the least-squares intercept and slope of values against their index
+func linearFit(values []float64) (float64, float64) {
+	n := float64(len(values))
+	if n == 0 {
+		return 0, 0
+	}
+
+	meanX, meanY := (n-1)/2, 0.0
+	for _, v := range values {
+		meanY += v
+	}
+	meanY /= n
+
+	var cov, variance float64
+	for i, v := range values {
+		dx := float64(i) - meanX
+		cov += dx * (v - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return meanY, 0
+	}
+	slope := cov / variance
+	return meanY - slope*meanX, slope
+}
+
+func nonNegative(v float64) float64 {
+	return math.Max(v, 0)
+}
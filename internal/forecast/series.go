@@ -0,0 +1,75 @@
+package forecast
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Point is the value of one day
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+// DailySeries totals costs per UTC day from start to end (exclusive), with a zero for
+// days without cost. Entries that span several days, such as the period totals New
+// Relic returns, are spread evenly over the days they cover.
+func DailySeries(costs []providers.CostData, start, end time.Time) []Point {
+	start, end = day(start), day(end)
+	days := int(end.Sub(start).Hours() / 24)
+	if days <= 0 {
+		return nil
+	}
+
+	series := make([]Point, days)
+	for i := range series {
+		series[i].Date = start.AddDate(0, 0, i)
+	}
+
+	for _, c := range costs {
+		from, to := day(c.StartTime), day(c.EndTime)
+		if !to.After(from) {
+			to = from.AddDate(0, 0, 1)
+		}
+		span := int(to.Sub(from).Hours() / 24)
+		perDay := c.Cost / float64(span)
+
+		for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+			i := int(d.Sub(start).Hours() / 24)
+			if i >= 0 && i < days {
+				series[i].Value += perDay
+			}
+		}
+	}
+
+	return series
+}
+
+// CheckDaily returns an error when a cost entry covers more than one day. Spreading
+// such a total evenly over its days would leave no trend or weekday pattern to fit.
+func CheckDaily(costs []providers.CostData) error {
+	for _, c := range costs {
+		if day(c.EndTime).Sub(day(c.StartTime)) > 24*time.Hour {
+			return fmt.Errorf("forecasting needs daily cost history, but the provider reports the cost from %s to %s as one total",
+				c.StartTime.Format("2006-01-02"), c.EndTime.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// Values returns the values of the points
+func Values(points []Point) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// day truncates t to midnight UTC
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
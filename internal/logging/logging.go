@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Supported log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options controls how diagnostic logging is emitted
+type Options struct {
+	Verbose bool      // Include debug messages
+	Quiet   bool      // Only report errors
+	Format  string    // text or json
+	Writer  io.Writer // Defaults to stderr so stdout stays clean for report output
+}
+
+// Setup installs a leveled structured logger as the default slog logger.
+// Reports are written to stdout, so all diagnostics go to stderr unless
+// another writer is given.
+func Setup(opts Options) error {
+	if opts.Verbose && opts.Quiet {
+		return fmt.Errorf("--verbose and --quiet cannot be used together")
+	}
+
+	writer := opts.Writer
+	if writer == nil {
+		writer = os.Stderr
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case opts.Verbose:
+		level = slog.LevelDebug
+	case opts.Quiet:
+		level = slog.LevelError
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(writer, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(writer, handlerOpts)
+	default:
+		return fmt.Errorf("unsupported log format: %s (use text or json)", opts.Format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package pricing is an offline price catalog for estimating cost from usage when a
+// provider's billing API is not available. A default catalog of list prices is built
+// in; prices imported from a JSON or CSV file override it.
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+//go:embed default_catalog.json
+var defaultCatalogJSON []byte
+
+// Price is the price of one unit of a usage metric
+type Price struct {
+	Provider string  `json:"provider"`
+	Region   string  `json:"region,omitempty"` // Empty applies to every region without its own price
+	Option   string  `json:"option,omitempty"` // Pricing option, e.g. a New Relic data option or a log class
+	Service  string  `json:"service"`          // UsageData service, e.g. CloudWatch, or * for any
+	Metric   string  `json:"metric"`           // UsageData metric, e.g. IncomingBytes
+	Unit     string  `json:"unit"`             // Unit of the usage the price is for, e.g. GB
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+
+	// Monthly prices a quantity held for a month, such as a metric or a user, rather
+	// than a quantity consumed, such as GB ingested
+	Monthly bool `json:"monthly,omitempty"`
+
+	// CostService and Item name the cost entries estimated with this price, as the
+	// billing API would; they default to the usage service and metric
+	CostService string `json:"costService,omitempty"`
+	Item        string `json:"item,omitempty"`
+
+	Description string `json:"description,omitempty"`
+}
+
+// Catalog is a list of prices
+type Catalog struct {
+	Version string  `json:"version"`
+	Prices  []Price `json:"prices"`
+}
+
+// key identifies the prices that replace each other when catalogs are merged
+func (p Price) key() string {
+	return strings.ToLower(strings.Join([]string{p.Provider, p.Region, p.Option, p.Service, p.Metric}, "|"))
+}
+
+func (p *Price) validate() error {
+	if p.Provider == "" || p.Service == "" || p.Metric == "" {
+		return fmt.Errorf("price needs a provider, service and metric")
+	}
+	if p.Price < 0 {
+		return fmt.Errorf("price of %s %s must not be negative, got %g", p.Provider, p.Metric, p.Price)
+	}
+	p.Provider = strings.ToLower(p.Provider)
+	if p.Currency == "" {
+		p.Currency = "USD"
+	}
+	return nil
+}
+
+// Default returns a copy of the built-in catalog of list prices
+func Default() *Catalog {
+	var catalog Catalog
+	if err := json.Unmarshal(defaultCatalogJSON, &catalog); err != nil {
+		panic(fmt.Sprintf("invalid built-in pricing catalog: %v", err))
+	}
+	return &catalog
+}
+
+// Merge returns the catalog with the prices of overrides added, replacing prices for
+// the same provider, region, option, service and metric
+func (c *Catalog) Merge(overrides *Catalog) *Catalog {
+	merged := &Catalog{Version: c.Version, Prices: append([]Price{}, c.Prices...)}
+	if overrides.Version != "" {
+		merged.Version = overrides.Version
+	}
+
+	index := make(map[string]int, len(merged.Prices))
+	for i, p := range merged.Prices {
+		index[p.key()] = i
+	}
+	for _, p := range overrides.Prices {
+		if i, ok := index[p.key()]; ok {
+			merged.Prices[i] = p
+			continue
+		}
+		index[p.key()] = len(merged.Prices)
+		merged.Prices = append(merged.Prices, p)
+	}
+	return merged
+}
+
+// AnyService is the service of prices that apply to a metric of every service, such as
+// New Relic data ingested under any product line
+const AnyService = "*"
+
+// Lookup finds the price of a usage metric. A price for the service wins over one for
+// AnyService; then a price for the option wins over one for the region, which wins over
+// one that applies to every region and option.
+func (c *Catalog) Lookup(provider, region, option, service, metric string) (Price, bool) {
+	best, bestScore := Price{}, -1
+	for _, p := range c.Prices {
+		if !strings.EqualFold(p.Provider, provider) || !strings.EqualFold(p.Metric, metric) {
+			continue
+		}
+		score := 0
+		if p.Service != AnyService {
+			if !strings.EqualFold(p.Service, service) {
+				continue
+			}
+			score += 4
+		}
+		if p.Region != "" {
+			if !strings.EqualFold(p.Region, region) {
+				continue
+			}
+			score++
+		}
+		if p.Option != "" {
+			if !strings.EqualFold(p.Option, option) {
+				continue
+			}
+			score += 2
+		}
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// Provider returns the prices of one provider
+func (c *Catalog) Provider(provider string) []Price {
+	var prices []Price
+	for _, p := range c.Prices {
+		if strings.EqualFold(p.Provider, provider) {
+			prices = append(prices, p)
+		}
+	}
+	return prices
+}
+
+var (
+	configuredMu      sync.Mutex
+	configuredPath    string
+	configuredCatalog *Catalog
+)
+
+// FromConfig returns the built-in catalog merged with the file in pricing.catalog, if
+// any. The result is cached until the configured file changes.
+func FromConfig(config *viper.Viper) (*Catalog, error) {
+	path := config.GetString("pricing.catalog")
+
+	configuredMu.Lock()
+	defer configuredMu.Unlock()
+	if configuredCatalog != nil && configuredPath == path {
+		return configuredCatalog, nil
+	}
+
+	catalog := Default()
+	if path != "" {
+		imported, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		catalog = catalog.Merge(imported)
+	}
+
+	configuredPath, configuredCatalog = path, catalog
+	return catalog, nil
+}
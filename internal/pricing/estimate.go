@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Periods of estimated cost entries, as in CostData.Period
+const (
+	PeriodDaily   = "Daily"
+	PeriodMonthly = "Monthly"
+)
+
+// Scope selects the prices that apply to a provider's usage
+type Scope struct {
+	Provider string
+	Region   string
+	Option   string // e.g. the New Relic data option
+}
+
+// Estimate prices usage with the catalog, with one entry per period, account, service
+// and item. Daily estimates cost each usage entry on the day of its timestamp and
+// prorate monthly prices over the days of that month. Monthly estimates cost all usage
+// over start to end and charge monthly prices in full. Usage priced at zero, i.e. not
+// billed on its own, is skipped; usage without a price is skipped with a warning.
+func (c *Catalog) Estimate(scope Scope, usage []providers.UsageData, period string, start, end time.Time) []providers.CostData {
+	type estimate struct {
+		cost  providers.CostData
+		price Price
+	}
+	var estimates []*estimate
+	index := make(map[string]*estimate)
+	var unpriced []string
+	seen := make(map[string]bool)
+
+	for _, u := range usage {
+		price, ok := c.Lookup(scope.Provider, scope.Region, scope.Option, u.Service, u.Metric)
+		if !ok {
+			if name := u.Service + "/" + u.Metric; !seen[name] {
+				seen[name] = true
+				unpriced = append(unpriced, name)
+			}
+			continue
+		}
+		if price.Price == 0 {
+			continue
+		}
+
+		periodStart, periodEnd := start, end
+		cost := u.Value * price.Price
+		if period == PeriodDaily {
+			periodStart = time.Date(u.Timestamp.Year(), u.Timestamp.Month(), u.Timestamp.Day(), 0, 0, 0, 0, time.UTC)
+			periodEnd = periodStart.AddDate(0, 0, 1)
+			if price.Monthly {
+				cost /= float64(daysInMonth(periodStart))
+			}
+		}
+
+		accountID, _ := u.Metadata["accountId"].(string)
+		service, item := price.CostService, price.Item
+		if service == "" {
+			service = u.Service
+		}
+		if item == "" {
+			item = u.Metric
+		}
+
+		key := fmt.Sprintf("%s|%s|%s|%s", periodStart.Format("2006-01-02"), accountID, service, item)
+		e, ok := index[key]
+		if !ok {
+			e = &estimate{
+				cost: providers.CostData{
+					Service:   service,
+					ItemName:  item,
+					Currency:  price.Currency,
+					Period:    period,
+					StartTime: periodStart,
+					EndTime:   periodEnd,
+					AccountID: accountID,
+					Region:    scope.Region,
+					UsageUnit: price.Unit,
+					Estimated: true,
+				},
+				price: price,
+			}
+			index[key] = e
+			estimates = append(estimates, e)
+		}
+		e.cost.Cost += cost
+		e.cost.Quantity += u.Value
+	}
+
+	if len(unpriced) > 0 {
+		sort.Strings(unpriced)
+		slog.Warn("no catalog price for usage metrics; their cost is not estimated", "provider", scope.Provider, "metrics", strings.Join(unpriced, ", "))
+	}
+
+	costs := make([]providers.CostData, 0, len(estimates))
+	for _, e := range estimates {
+		e.cost.Description = e.price.describe(e.cost.Quantity)
+		costs = append(costs, e.cost)
+	}
+	return costs
+}
+
+// describe explains an estimate, e.g. "Estimated: 12.5 GB at 0.5 USD per GB"
+func (p Price) describe(quantity float64) string {
+	per := p.Unit
+	if p.Monthly {
+		per += "-month"
+	}
+	return fmt.Sprintf("Estimated: %s %s at %s %s per %s",
+		strconv.FormatFloat(math.Round(quantity*1000)/1000, 'f', -1, 64), p.Unit,
+		strconv.FormatFloat(p.Price, 'f', -1, 64), p.Currency, per)
+}
+
+// daysInMonth returns the number of days in the month of t
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,124 @@
+package pricing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CSVColumns is the column order of catalogs in CSV; provider, service, metric and
+// price are required
+var CSVColumns = []string{
+	"provider", "region", "option", "service", "metric", "unit", "price", "currency",
+	"monthly", "cost_service", "item", "description",
+}
+
+// LoadFile reads a catalog from a JSON file, in the format of the built-in catalog, or
+// from a CSV file with a header row of CSVColumns
+func LoadFile(path string) (*Catalog, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening pricing catalog: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadJSON(file)
+	case ".csv":
+		return ReadCSV(file)
+	default:
+		return nil, fmt.Errorf("unsupported pricing catalog %s (use a .json or .csv file)", path)
+	}
+}
+
+// ReadJSON reads a catalog in the format of the built-in catalog
+func ReadJSON(r io.Reader) (*Catalog, error) {
+	var catalog Catalog
+	if err := json.NewDecoder(r).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("error reading pricing catalog: %w", err)
+	}
+	for i := range catalog.Prices {
+		if err := catalog.Prices[i].validate(); err != nil {
+			return nil, fmt.Errorf("pricing catalog entry %d: %w", i+1, err)
+		}
+	}
+	return &catalog, nil
+}
+
+// ReadCSV reads a catalog from CSV with a header row of CSVColumns
+func ReadCSV(r io.Reader) (*Catalog, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading pricing catalog CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return &Catalog{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"provider", "service", "metric", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("pricing catalog CSV has no %s column", name)
+		}
+	}
+
+	catalog := &Catalog{}
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		price, err := strconv.ParseFloat(field("price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("pricing catalog CSV line %d: invalid price %q", line+2, field("price"))
+		}
+		monthly := false
+		if v := field("monthly"); v != "" {
+			if monthly, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("pricing catalog CSV line %d: invalid monthly %q", line+2, v)
+			}
+		}
+
+		p := Price{
+			Provider:    field("provider"),
+			Region:      field("region"),
+			Option:      field("option"),
+			Service:     field("service"),
+			Metric:      field("metric"),
+			Unit:        field("unit"),
+			Price:       price,
+			Currency:    field("currency"),
+			Monthly:     monthly,
+			CostService: field("cost_service"),
+			Item:        field("item"),
+			Description: field("description"),
+		}
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("pricing catalog CSV line %d: %w", line+2, err)
+		}
+		catalog.Prices = append(catalog.Prices, p)
+	}
+
+	return catalog, nil
+}
+
+// CSVRecord formats a price in the order of CSVColumns
+func (p Price) CSVRecord() []string {
+	return []string{
+		p.Provider, p.Region, p.Option, p.Service, p.Metric, p.Unit,
+		strconv.FormatFloat(p.Price, 'f', -1, 64), p.Currency, strconv.FormatBool(p.Monthly),
+		p.CostService, p.Item, p.Description,
+	}
+}
@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
@@ -29,7 +30,7 @@ func NewCloudWatchProvider(config *viper.Viper) (*CloudWatchProvider, error) {
 	region := config.GetString("aws.region")
 	profile := config.GetString("aws.profile")
 
-	fmt.Printf("Initializing CloudWatch provider with region: %s and profile: %s\n", region, profile)
+	slog.Debug("initializing CloudWatch provider", "region", region, "profile", profile)
 
 	// Ensure we explicitly set the region in our AWS config
 	var opts []func(*awsconfig.LoadOptions) error
@@ -123,7 +124,7 @@ func (c *CloudWatchProvider) GetUsageData(start, end time.Time) ([]providers.Usa
 
 		resp, err := c.client.GetMetricStatistics(context.TODO(), input)
 		if err != nil {
-			fmt.Printf("Warning: error getting metrics for %s: %v\n", metric, err)
+			slog.Warn("error getting CloudWatch metric", "metric", metric, "error", err)
 			continue // Skip this metric but continue with others
 		}
 
@@ -154,29 +155,45 @@ func (c *CloudWatchProvider) GetUsageData(start, end time.Time) ([]providers.Usa
 	return result, nil
 }
 
-// GetCostData retrieves cost data related to CloudWatch using AWS Cost Explorer API
+// GetCostData retrieves CloudWatch cost from Cost Explorer or, depending on
+// aws.cost_source, estimates it from usage with the pricing catalog
 func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.CostData, error) {
-	// Create Cost Explorer client using the same region and profile
-	opts := []func(*awsconfig.LoadOptions) error{
-		awsconfig.WithRegion(c.region),
+	source, err := providers.ParseCostSource(viper.GetString("aws.cost_source"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid aws.cost_source: %w", err)
 	}
 
-	// Add profile if it's set
-	if c.profile != "" {
-		opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+	switch source {
+	case providers.CostSourceEstimate:
+		return c.EstimateCostData(start, end)
+	case providers.CostSourceBilling:
+		return c.getBilledCostData(start, end)
 	}
 
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config for Cost Explorer: %w", err)
+	billed, err := c.getBilledCostData(start, end)
+	if err == nil {
+		return billed, nil
+	}
+	estimated, estimateErr := c.EstimateCostData(start, end)
+	if estimateErr != nil || len(estimated) == 0 {
+		slog.Debug("could not estimate cost from usage", "error", estimateErr)
+		return nil, err
 	}
+	slog.Warn("Cost Explorer data unavailable, estimating cost from usage with the pricing catalog", "error", err)
+	return estimated, nil
+}
 
-	ceClient := costexplorer.NewFromConfig(cfg)
+// getBilledCostData retrieves cost data related to CloudWatch using AWS Cost Explorer API
+func (c *CloudWatchProvider) getBilledCostData(start, end time.Time) ([]providers.CostData, error) {
+	ceClient, err := c.costExplorerClient()
+	if err != nil {
+		return nil, err
+	}
 
 	// Ensure the end date is exclusive
 	endDate := end.AddDate(0, 0, 1)
 
-	fmt.Printf("Querying AWS Cost Explorer from %s to %s\n", start.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	slog.Debug("querying AWS Cost Explorer", "start", start.Format("2006-01-02"), "end", endDate.Format("2006-01-02"))
 
 	// Keep the original input with only two GroupBy dimensions (AWS limit)
 	input := &costexplorer.GetCostAndUsageInput{
@@ -196,28 +213,7 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 				Key:  stringPtr("LINKED_ACCOUNT"),
 			},
 		},
-		Filter: &cetypes.Expression{
-			Or: []cetypes.Expression{
-				{
-					Dimensions: &cetypes.DimensionValues{
-						Key:    cetypes.DimensionService,
-						Values: []string{"AmazonCloudWatch", "CloudWatch"},
-					},
-				},
-				{
-					Dimensions: &cetypes.DimensionValues{
-						Key:    cetypes.DimensionService,
-						Values: []string{"AmazonCloudWatchLogs", "CloudWatchLogs"},
-					},
-				},
-				{
-					Dimensions: &cetypes.DimensionValues{
-						Key:    cetypes.DimensionService,
-						Values: []string{"AmazonCloudWatchMetrics", "CloudWatchMetrics"},
-					},
-				},
-			},
-		},
+		Filter: cloudWatchServiceFilter(),
 	}
 
 	// Execute the query
@@ -226,7 +222,7 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 		return nil, fmt.Errorf("error getting cost data from AWS Cost Explorer: %w", err)
 	}
 
-	fmt.Printf("Cost Explorer returned %d result periods\n", len(resp.ResultsByTime))
+	slog.Debug("Cost Explorer returned result periods", "periods", len(resp.ResultsByTime))
 
 	// Process the results
 	var results []providers.CostData
@@ -237,11 +233,11 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 
 		// Check for totals
 		if resultByTime.Total != nil && resultByTime.Total["UnblendedCost"].Amount != nil {
-			fmt.Printf("Total cost for period %s to %s: %s %s\n",
-				*resultByTime.TimePeriod.Start,
-				*resultByTime.TimePeriod.End,
-				*resultByTime.Total["UnblendedCost"].Amount,
-				*resultByTime.Total["UnblendedCost"].Unit)
+			slog.Debug("total cost for period",
+				"start", *resultByTime.TimePeriod.Start,
+				"end", *resultByTime.TimePeriod.End,
+				"amount", *resultByTime.Total["UnblendedCost"].Amount,
+				"unit", *resultByTime.Total["UnblendedCost"].Unit)
 		}
 
 		// Process each service group
@@ -280,8 +276,9 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 			usageUnit, description := inferUsageInfo(serviceName, usage)
 
 			// Always include the entry, even with zero cost
-			fmt.Printf("CloudWatch service: %s, Account: %s, Date: %s, Cost: %.6f %s, Usage: %.2f %s (%s)\n",
-				serviceName, accountId, periodStart.Format("2006-01-02"), cost, currency, usage, usageUnit, description)
+			slog.Debug("CloudWatch cost entry", "service", serviceName, "account", accountId,
+				"date", periodStart.Format("2006-01-02"), "cost", cost, "currency", currency,
+				"usage", usage, "usageUnit", usageUnit, "description", description)
 
 			results = append(results, providers.CostData{
 				Service:     serviceName,
@@ -302,7 +299,7 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 
 	// If we still have no results, try without the filter as a fallback
 	if len(results) == 0 {
-		fmt.Println("No CloudWatch services found with filters, trying without filters...")
+		slog.Info("no CloudWatch services found with filters, trying without filters")
 
 		// Remove the filter and try again
 		input.Filter = nil
@@ -358,8 +355,8 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 						}
 					}
 
-					fmt.Printf("Found CloudWatch service: %s, Account: %s, Cost: %.6f %s, Usage: %.2f\n",
-						serviceName, accountId, cost, currency, usage)
+					slog.Debug("found CloudWatch service without filters", "service", serviceName,
+						"account", accountId, "cost", cost, "currency", currency, "usage", usage)
 
 					results = append(results, providers.CostData{
 						Service:   serviceName,
@@ -384,6 +381,51 @@ func (c *CloudWatchProvider) GetCostData(start, end time.Time) ([]providers.Cost
 	return results, nil
 }
 
+// costExplorerClient creates a Cost Explorer client using the same region and profile
+func (c *CloudWatchProvider) costExplorerClient() (*costexplorer.Client, error) {
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(c.region),
+	}
+
+	// Add profile if it's set
+	if c.profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for Cost Explorer: %w", err)
+	}
+
+	return costexplorer.NewFromConfig(cfg), nil
+}
+
+// cloudWatchServiceFilter restricts Cost Explorer queries to the CloudWatch services
+func cloudWatchServiceFilter() *cetypes.Expression {
+	return &cetypes.Expression{
+		Or: []cetypes.Expression{
+			{
+				Dimensions: &cetypes.DimensionValues{
+					Key:    cetypes.DimensionService,
+					Values: []string{"AmazonCloudWatch", "CloudWatch"},
+				},
+			},
+			{
+				Dimensions: &cetypes.DimensionValues{
+					Key:    cetypes.DimensionService,
+					Values: []string{"AmazonCloudWatchLogs", "CloudWatchLogs"},
+				},
+			},
+			{
+				Dimensions: &cetypes.DimensionValues{
+					Key:    cetypes.DimensionService,
+					Values: []string{"AmazonCloudWatchMetrics", "CloudWatchMetrics"},
+				},
+			},
+		},
+	}
+}
+
 // inferUsageInfo infers usage type information based on the service name
 func inferUsageInfo(serviceName string, usage float64) (string, string) {
 	// Default values
@@ -0,0 +1,154 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// logGroupSearch finds the daily ingested bytes of every log group in one query
+const logGroupSearch = `SEARCH('{AWS/Logs,LogGroupName} MetricName="IncomingBytes"', 'Sum', 86400)`
+
+// GetDimensionUsage breaks usage down by log group (ingested GB per day) or by a cost
+// allocation tag (CloudWatch cost per day and account)
+func (c *CloudWatchProvider) GetDimensionUsage(dimension string, start, end time.Time) ([]providers.UsageData, error) {
+	if dimension == providers.DimensionLogGroup {
+		return c.getLogGroupUsage(start, end.AddDate(0, 0, 1))
+	}
+	if key, ok := providers.TagDimension(dimension); ok {
+		return c.getTagCost(key, start, end)
+	}
+	return nil, fmt.Errorf("AWS CloudWatch cannot break usage down by %s (use %s or %s<key>)", dimension, providers.DimensionLogGroup, providers.DimensionTagPrefix)
+}
+
+// getLogGroupUsage returns the GB ingested by each log group per day
+func (c *CloudWatchProvider) getLogGroupUsage(start, end time.Time) ([]providers.UsageData, error) {
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: &start,
+		EndTime:   &end,
+		MetricDataQueries: []types.MetricDataQuery{
+			{
+				Id:         stringPtr("logGroups"),
+				Expression: stringPtr(logGroupSearch),
+				Label:      stringPtr("${PROP('Dim.LogGroupName')}"),
+			},
+		},
+	}
+
+	var usage []providers.UsageData
+	for {
+		resp, err := c.client.GetMetricData(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("error getting log group usage: %w", err)
+		}
+
+		for _, result := range resp.MetricDataResults {
+			if result.Label == nil {
+				continue
+			}
+			for i, timestamp := range result.Timestamps {
+				if i >= len(result.Values) {
+					break
+				}
+				usage = append(usage, providers.UsageData{
+					Service:   "CloudWatch",
+					Metric:    "IncomingBytes",
+					Value:     result.Values[i] / (1024 * 1024 * 1024), // Bytes to GB
+					Unit:      "GB",
+					Timestamp: timestamp,
+					Metadata: map[string]interface{}{
+						providers.DimensionLogGroup: *result.Label,
+					},
+				})
+			}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	slog.Debug("fetched log group usage", "entries", len(usage))
+	return usage, nil
+}
+
+// getTagCost returns the daily CloudWatch cost of each account and value of a cost
+// allocation tag. Untagged cost has an empty value.
+func (c *CloudWatchProvider) getTagCost(key string, start, end time.Time) ([]providers.UsageData, error) {
+	ceClient, err := c.costExplorerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	dimension := providers.DimensionTagPrefix + key
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &cetypes.DateInterval{
+			Start: stringPtr(start.Format("2006-01-02")),
+			End:   stringPtr(end.AddDate(0, 0, 1).Format("2006-01-02")),
+		},
+		Granularity: cetypes.GranularityDaily,
+		Metrics:     []string{"UnblendedCost"},
+		GroupBy: []cetypes.GroupDefinition{
+			{Type: cetypes.GroupDefinitionTypeDimension, Key: stringPtr("LINKED_ACCOUNT")},
+			{Type: cetypes.GroupDefinitionTypeTag, Key: stringPtr(key)},
+		},
+		Filter: cloudWatchServiceFilter(),
+	}
+
+	var usage []providers.UsageData
+	for {
+		resp, err := ceClient.GetCostAndUsage(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("error getting cost by tag %s from AWS Cost Explorer: %w", key, err)
+		}
+
+		for _, resultByTime := range resp.ResultsByTime {
+			periodStart, _ := time.Parse("2006-01-02", *resultByTime.TimePeriod.Start)
+			for _, group := range resultByTime.Groups {
+				if len(group.Keys) < 2 || group.Metrics["UnblendedCost"].Amount == nil {
+					continue
+				}
+				cost, err := strconv.ParseFloat(*group.Metrics["UnblendedCost"].Amount, 64)
+				if err != nil {
+					continue
+				}
+				unit := "USD"
+				if group.Metrics["UnblendedCost"].Unit != nil {
+					unit = *group.Metrics["UnblendedCost"].Unit
+				}
+
+				// Tag groups come back as "key$value"
+				_, value, _ := strings.Cut(group.Keys[1], "$")
+				usage = append(usage, providers.UsageData{
+					Service:   "CloudWatch",
+					Metric:    "UnblendedCost",
+					Value:     cost,
+					Unit:      unit,
+					Timestamp: periodStart,
+					Metadata: map[string]interface{}{
+						"accountId": group.Keys[0],
+						dimension:   value,
+					},
+				})
+			}
+		}
+
+		if resp.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = resp.NextPageToken
+	}
+
+	slog.Debug("fetched cost by tag", "tag", key, "entries", len(usage))
+	return usage, nil
+}
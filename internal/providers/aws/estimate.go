@@ -0,0 +1,26 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// EstimateCostData estimates the daily CloudWatch cost of the region from its usage
+// metrics and the pricing catalog, for when Cost Explorer is not available
+func (c *CloudWatchProvider) EstimateCostData(start, end time.Time) ([]providers.CostData, error) {
+	catalog, err := c.pricingCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	// Like Cost Explorer queries, the end date is inclusive
+	usage, err := c.GetUsageData(start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	scope := pricing.Scope{Provider: "aws", Region: c.region}
+	return catalog.Estimate(scope, usage, pricing.PeriodDaily, start, end), nil
+}
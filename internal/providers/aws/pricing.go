@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/ilhicas/observability-cost-center/internal/simulate"
+	"github.com/spf13/viper"
+)
+
+// priceOverrides are the aws.pricing keys that override catalog prices in every region
+var priceOverrides = []struct {
+	key, option, metric string
+}{
+	{"aws.pricing.logs_ingest_per_gb", "", "IncomingBytes"},
+	{"aws.pricing.logs_infrequent_access_ingest_per_gb", simulate.ClassInfrequentAccess, "IncomingBytes"},
+	{"aws.pricing.logs_storage_per_gb_month", "", "StoredBytes"},
+}
+
+// pricingCatalog returns the configured pricing catalog with the aws.pricing overrides
+// applied to the provider's region
+func (c *CloudWatchProvider) pricingCatalog() (*pricing.Catalog, error) {
+	catalog, err := pricing.FromConfig(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := &pricing.Catalog{}
+	for _, o := range priceOverrides {
+		price := viper.GetFloat64(o.key)
+		if price <= 0 {
+			continue
+		}
+		p, ok := catalog.Lookup("aws", c.region, o.option, "CloudWatch", o.metric)
+		if !ok {
+			continue
+		}
+		p.Region, p.Option, p.Price = c.region, o.option, price
+		overrides.Prices = append(overrides.Prices, p)
+	}
+	return catalog.Merge(overrides), nil
+}
+
+// catalogPrice returns the price of a CloudWatch metric in the provider's region, or 0
+// when the catalog has none
+func (c *CloudWatchProvider) catalogPrice(catalog *pricing.Catalog, option, metric string) float64 {
+	p, _ := catalog.Lookup("aws", c.region, option, "CloudWatch", metric)
+	return p.Price
+}
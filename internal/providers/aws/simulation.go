@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/simulate"
+)
+
+// logsClient creates a CloudWatch Logs client using the same region and profile
+func (c *CloudWatchProvider) logsClient() (*cloudwatchlogs.Client, error) {
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(c.region),
+	}
+
+	if c.profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for CloudWatch Logs: %w", err)
+	}
+
+	return cloudwatchlogs.NewFromConfig(cfg), nil
+}
+
+// SimulationBaseline describes every log group of the region: its retention, class,
+// stored data and the GB it ingested over the last 30 days
+func (c *CloudWatchProvider) SimulationBaseline() (*simulate.Baseline, error) {
+	client, err := c.logsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	usage, err := c.getLogGroupUsage(end.AddDate(0, 0, -30), end)
+	if err != nil {
+		return nil, err
+	}
+	ingested := make(map[string]float64)
+	for _, u := range usage {
+		if name, ok := u.Metadata[providers.DimensionLogGroup].(string); ok {
+			ingested[name] += u.Value
+		}
+	}
+
+	catalog, err := c.pricingCatalog()
+	if err != nil {
+		return nil, err
+	}
+	baseline := &simulate.Baseline{
+		Provider: "aws",
+		Pricing: simulate.Pricing{
+			Currency:                    "USD",
+			IngestPerGB:                 c.catalogPrice(catalog, "", "IncomingBytes"),
+			InfrequentAccessIngestPerGB: c.catalogPrice(catalog, simulate.ClassInfrequentAccess, "IncomingBytes"),
+			StoragePerGBMonth:           c.catalogPrice(catalog, "", "StoredBytes"),
+		},
+	}
+
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client, &cloudwatchlogs.DescribeLogGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("error describing log groups: %w", err)
+		}
+
+		for _, group := range page.LogGroups {
+			if group.LogGroupName == nil {
+				continue
+			}
+			name := *group.LogGroupName
+			resource := simulate.Resource{
+				Kind:      simulate.KindLogGroup,
+				AccountID: arnAccountID(group.Arn),
+				Name:      name,
+				MonthlyGB: ingested[name],
+				Class:     string(group.LogGroupClass),
+			}
+			if resource.Class == "" {
+				resource.Class = simulate.ClassStandard
+			}
+			if group.RetentionInDays != nil {
+				resource.RetentionDays = int(*group.RetentionInDays)
+			}
+			if group.StoredBytes != nil {
+				resource.StoredGB = float64(*group.StoredBytes) / (1024 * 1024 * 1024) // Bytes to GB
+			}
+			if group.MetricFilterCount != nil {
+				resource.MetricFilters = int(*group.MetricFilterCount)
+			}
+			baseline.Resources = append(baseline.Resources, resource)
+		}
+	}
+
+	slog.Debug("fetched simulation baseline", "provider", "aws", "logGroups", len(baseline.Resources))
+	return baseline, nil
+}
+
+// arnAccountID returns the account of an ARN such as arn:aws:logs:<region>:<account>:log-group:<name>
+func arnAccountID(arn *string) string {
+	if arn == nil {
+		return ""
+	}
+	parts := strings.SplitN(*arn, ":", 6)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Dimensions that providers can break usage down by, for splitting shared cost in
+// proportion to usage. Resource tags are "tag:<key>", e.g. "tag:team".
+const (
+	DimensionLogGroup  = "logGroup"
+	DimensionApp       = "app"
+	DimensionUserGroup = "userGroup"
+	DimensionTagPrefix = "tag:"
+)
+
+// DimensionUsageProvider is implemented by providers that can break usage down by a
+// dimension. Each returned entry carries the dimension value in its Metadata under the
+// dimension name, and the account in "accountId" when it is known. Like the cost
+// queries, end is the inclusive last day.
+type DimensionUsageProvider interface {
+	GetDimensionUsage(dimension string, start, end time.Time) ([]UsageData, error)
+}
+
+// TagDimension returns the tag key of a "tag:<key>" dimension
+func TagDimension(dimension string) (string, bool) {
+	key, ok := strings.CutPrefix(dimension, DimensionTagPrefix)
+	return key, ok && key != ""
+}
+
+// MatchPattern matches a case-insensitive pattern where * matches any run of
+// characters, including "/" in log group names. An empty pattern matches everything.
+func MatchPattern(pattern, value string) bool {
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+	if !strings.Contains(pattern, "*") {
+		return strings.EqualFold(pattern, value)
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(value)
+}
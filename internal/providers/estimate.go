@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cost sources a provider reads CostData from, set per provider as <provider>.cost_source
+const (
+	// CostSourceAuto reads billing data and estimates cost from usage when billing
+	// data is unavailable
+	CostSourceAuto = "auto"
+
+	// CostSourceBilling only reads billing data
+	CostSourceBilling = "billing"
+
+	// CostSourceEstimate always estimates cost from usage with the pricing catalog
+	CostSourceEstimate = "estimate"
+)
+
+// CostEstimator is implemented by providers that can estimate cost from their usage
+// with the pricing catalog. Estimated entries have Estimated set.
+type CostEstimator interface {
+	EstimateCostData(start, end time.Time) ([]CostData, error)
+}
+
+// ParseCostSource validates a configured cost source, defaulting to CostSourceAuto
+func ParseCostSource(source string) (string, error) {
+	switch source = strings.ToLower(source); source {
+	case "":
+		return CostSourceAuto, nil
+	case CostSourceAuto, CostSourceBilling, CostSourceEstimate:
+		return source, nil
+	default:
+		return "", fmt.Errorf("unknown cost source %q (use %s, %s or %s)", source, CostSourceAuto, CostSourceBilling, CostSourceEstimate)
+	}
+}
@@ -0,0 +1,211 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// License attribution modes supported by newrelic.license_attribution
+const (
+	LicenseAttributionOrganization = "organization"
+	LicenseAttributionAccount      = "account"
+	LicenseAttributionNone         = "none"
+)
+
+// AccountInfo identifies a New Relic account visible to the API key
+type AccountInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// OrganizationInfo identifies the New Relic organization that owns the accounts
+type OrganizationInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AccountFilter restricts which New Relic accounts the provider queries.
+// An empty allowlist permits every account that is not explicitly denied.
+type AccountFilter struct {
+	Allow map[string]bool
+	Deny  map[string]bool
+}
+
+// NewAccountFilterFromConfig builds an account filter from the viper configuration.
+//
+// The following keys are honored:
+//
+//	newrelic.account_id        single account to report on (added to the allowlist)
+//	newrelic.account_ids       list of account IDs to allow, or a map with
+//	  allow: [...]             account IDs to allow
+//	  deny:  [...]             account IDs to always skip
+func NewAccountFilterFromConfig(config *viper.Viper) AccountFilter {
+	filter := AccountFilter{
+		Allow: make(map[string]bool),
+		Deny:  make(map[string]bool),
+	}
+
+	if accountID := strings.TrimSpace(config.GetString("newrelic.account_id")); accountID != "" && accountID != "YOUR_ACCOUNT_ID" {
+		filter.Allow[accountID] = true
+	}
+
+	// account_ids may either be a plain list (an allowlist) or an allow/deny map
+	switch config.Get("newrelic.account_ids").(type) {
+	case []interface{}, []string:
+		for _, id := range config.GetStringSlice("newrelic.account_ids") {
+			filter.Allow[strings.TrimSpace(id)] = true
+		}
+	case map[string]interface{}:
+		for _, id := range config.GetStringSlice("newrelic.account_ids.allow") {
+			filter.Allow[strings.TrimSpace(id)] = true
+		}
+		for _, id := range config.GetStringSlice("newrelic.account_ids.deny") {
+			filter.Deny[strings.TrimSpace(id)] = true
+		}
+	}
+
+	return filter
+}
+
+// Permits reports whether the given account ID passes the filter
+func (f AccountFilter) Permits(accountID string) bool {
+	if f.Deny[accountID] {
+		return false
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	return f.Allow[accountID]
+}
+
+// IsRestricted reports whether the filter narrows the set of accounts at all
+func (f AccountFilter) IsRestricted() bool {
+	return len(f.Allow) > 0 || len(f.Deny) > 0
+}
+
+// listAccounts returns the accounts visible to the API key that pass the account filter.
+// The result is cached for the lifetime of the provider.
+func (nr *NewRelicProvider) listAccounts() ([]AccountInfo, error) {
+	if nr.accounts != nil {
+		return nr.accounts, nil
+	}
+
+	accountsQuery := `{
+		actor {
+			accounts {
+				id
+				name
+			}
+		}
+	}`
+
+	// Execute the query to get all account IDs
+	accountsResp, err := nr.client.NerdGraph.Query(accountsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error querying account IDs: %w", err)
+	}
+
+	// Account IDs come back as numbers, so decode them through json.Number
+	var accountsResponse struct {
+		Actor struct {
+			Accounts []struct {
+				ID   json.Number `json:"id"`
+				Name string      `json:"name"`
+			} `json:"accounts"`
+		} `json:"actor"`
+	}
+
+	jsonData, err := json.Marshal(accountsResp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling account response: %w", err)
+	}
+
+	err = json.Unmarshal(jsonData, &accountsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling account response: %w", err)
+	}
+
+	accounts := make([]AccountInfo, 0, len(accountsResponse.Actor.Accounts))
+	for _, account := range accountsResponse.Actor.Accounts {
+		accountID := account.ID.String()
+		if !nr.accountFilter.Permits(accountID) {
+			continue
+		}
+		accounts = append(accounts, AccountInfo{ID: accountID, Name: account.Name})
+	}
+
+	slog.Debug("found New Relic accounts", "visible", len(accountsResponse.Actor.Accounts), "allowed", len(accounts))
+
+	if len(accounts) == 0 && nr.accountFilter.IsRestricted() {
+		return nil, fmt.Errorf("none of the accounts visible to the API key match newrelic.account_id/newrelic.account_ids")
+	}
+
+	nr.accounts = accounts
+	return accounts, nil
+}
+
+// getOrganization returns the organization that owns the accounts. User licenses
+// are billed at the organization level, so license cost is attributed to it by default.
+func (nr *NewRelicProvider) getOrganization() (OrganizationInfo, error) {
+	query := `{
+		actor {
+			organization {
+				id
+				name
+			}
+		}
+	}`
+
+	resp, err := nr.client.NerdGraph.Query(query, nil)
+	if err != nil {
+		return OrganizationInfo{}, fmt.Errorf("error querying organization: %w", err)
+	}
+
+	var response struct {
+		Actor struct {
+			Organization OrganizationInfo `json:"organization"`
+		} `json:"actor"`
+	}
+
+	jsonData, err := json.Marshal(resp)
+	if err != nil {
+		return OrganizationInfo{}, fmt.Errorf("error marshalling organization response: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return OrganizationInfo{}, fmt.Errorf("error unmarshalling organization response: %w", err)
+	}
+
+	return response.Actor.Organization, nil
+}
+
+// licenseAttributionTarget resolves which account (or organization) license cost
+// should be attributed to. It returns ok=false when license cost should be skipped.
+func (nr *NewRelicProvider) licenseAttributionTarget() (id string, name string, ok bool, err error) {
+	switch nr.licenseAttribution {
+	case LicenseAttributionNone:
+		return "", "", false, nil
+	case LicenseAttributionAccount:
+		accounts, err := nr.listAccounts()
+		if err != nil {
+			return "", "", false, err
+		}
+		if len(accounts) != 1 {
+			return "", "", false, fmt.Errorf("license_attribution %q requires exactly one account after filtering, found %d", LicenseAttributionAccount, len(accounts))
+		}
+		return accounts[0].ID, "Account: " + accounts[0].Name, true, nil
+	default:
+		org, err := nr.getOrganization()
+		if err != nil {
+			return "", "", false, err
+		}
+		if org.ID == "" {
+			return "unknown", "Organization: Unknown", true, nil
+		}
+		return org.ID, "Organization: " + org.Name, true, nil
+	}
+}
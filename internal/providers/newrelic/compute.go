@@ -0,0 +1,266 @@
+package newrelic
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// Attributes of the NrComputeUsage event used for CCU reporting
+const (
+	computeEventType       = "NrComputeUsage"
+	computeUsageAttr       = "usage"
+	computeCapabilityAttr  = "dimension_productCapability"
+	computeUserAttr        = "dimension_email"
+	computeDashboardAttr   = "dimension_dashboardName"
+	computeQueryCapability = "Queries"
+)
+
+// defaultCCUPrice is the list price per compute capacity unit, overridable via newrelic.pricing.ccu
+const defaultCCUPrice = 0.25
+
+// ComputeUsage is the CCU consumption of one capability in one account on one day
+type ComputeUsage struct {
+	AccountID   string    `json:"accountId"`
+	AccountName string    `json:"accountName"`
+	Capability  string    `json:"capability"`
+	Day         time.Time `json:"day"`
+	CCU         float64   `json:"ccu"`
+}
+
+// ComputeConsumer is a user or dashboard that drove query CCU consumption
+type ComputeConsumer struct {
+	AccountID string  `json:"accountId"`
+	Kind      string  `json:"kind"` // "user" or "dashboard"
+	Name      string  `json:"name"`
+	CCU       float64 `json:"ccu"`
+	Cost      float64 `json:"cost"`
+	SharePct  float64 `json:"sharePct"` // Share of the account's query CCUs
+}
+
+// ccuPrice returns the configured price per CCU
+func ccuPrice() float64 {
+	if price := viper.GetFloat64("newrelic.pricing.ccu"); price > 0 {
+		return price
+	}
+	return defaultCCUPrice
+}
+
+// GetComputeUsage returns daily CCU consumption per account and capability
+func (nr *NewRelicProvider) GetComputeUsage(start, end time.Time) ([]ComputeUsage, error) {
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []ComputeUsage
+	for _, account := range accounts {
+		nrql := fmt.Sprintf("SELECT sum(%s) AS ccu FROM %s SINCE '%s' UNTIL '%s' FACET %s TIMESERIES 1 day LIMIT MAX",
+			computeUsageAttr, computeEventType, start.Format("2006-01-02"), end.Format("2006-01-02"), computeCapabilityAttr)
+
+		results, err := nr.runNRQL(account.ID, nrql)
+		if err != nil {
+			return nil, fmt.Errorf("error querying compute usage for account %s: %w", account.ID, err)
+		}
+
+		for _, result := range results {
+			capability, ok := resultString(result, computeCapabilityAttr)
+			if !ok {
+				capability, _ = resultString(result, "facet")
+			}
+			ccu, ok := resultFloat(result, "ccu")
+			if !ok || ccu == 0 {
+				continue
+			}
+			begin, _ := resultFloat(result, "beginTimeSeconds")
+
+			usage = append(usage, ComputeUsage{
+				AccountID:   account.ID,
+				AccountName: account.Name,
+				Capability:  capability,
+				Day:         time.Unix(int64(begin), 0).UTC().Truncate(24 * time.Hour),
+				CCU:         ccu,
+			})
+		}
+	}
+
+	return usage, nil
+}
+
+// getComputeUsageData converts CCU consumption to usage data
+func (nr *NewRelicProvider) getComputeUsageData(start, end time.Time) ([]providers.UsageData, error) {
+	computeUsage, err := nr.GetComputeUsage(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	usageData := make([]providers.UsageData, 0, len(computeUsage))
+	for _, usage := range computeUsage {
+		usageData = append(usageData, providers.UsageData{
+			Service:   "Compute",
+			Metric:    "CCU " + usage.Capability,
+			Value:     usage.CCU,
+			Unit:      "CCU",
+			Timestamp: usage.Day,
+			Metadata: map[string]interface{}{
+				"accountId":   usage.AccountID,
+				"accountName": usage.AccountName,
+				"capability":  usage.Capability,
+			},
+		})
+	}
+
+	return usageData, nil
+}
+
+// getComputeCostData prices CCU consumption per account, day and capability
+func (nr *NewRelicProvider) getComputeCostData(start, end time.Time) ([]providers.CostData, error) {
+	computeUsage, err := nr.GetComputeUsage(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	price := ccuPrice()
+	costData := make([]providers.CostData, 0, len(computeUsage))
+	for _, usage := range computeUsage {
+		costData = append(costData, providers.CostData{
+			Service:     "Compute",
+			ItemName:    usage.Capability,
+			Cost:        usage.CCU * price,
+			Quantity:    usage.CCU,
+			UsageUnit:   "CCU",
+			Currency:    "USD",
+			Period:      "Daily",
+			StartTime:   usage.Day,
+			EndTime:     usage.Day.AddDate(0, 0, 1),
+			AccountID:   usage.AccountID,
+			Description: fmt.Sprintf("Compute %s (%s)", usage.Capability, usage.AccountName),
+			Estimated:   true, // Priced with newrelic.pricing.ccu, not billed
+		})
+	}
+
+	return costData, nil
+}
+
+// GetComputeConsumers attributes query CCUs to the users and dashboards that drove them,
+// returning the top consumers of each kind per account, largest first
+func (nr *NewRelicProvider) GetComputeConsumers(start, end time.Time, limit int) ([]ComputeConsumer, error) {
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	price := ccuPrice()
+
+	var consumers []ComputeConsumer
+	for _, account := range accounts {
+		window := fmt.Sprintf("WHERE %s = %s SINCE '%s' UNTIL '%s'", computeCapabilityAttr, nrqlString(computeQueryCapability),
+			start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+		// Total query CCUs give each consumer's share of the account
+		totals, err := nr.runNRQL(account.ID, fmt.Sprintf("SELECT sum(%s) AS ccu FROM %s %s", computeUsageAttr, computeEventType, window))
+		if err != nil {
+			return nil, fmt.Errorf("error querying query CCUs for account %s: %w", account.ID, err)
+		}
+		var total float64
+		if len(totals) > 0 {
+			total, _ = resultFloat(totals[0], "ccu")
+		}
+
+		for kind, attr := range map[string]string{"user": computeUserAttr, "dashboard": computeDashboardAttr} {
+			results, err := nr.runNRQL(account.ID, fmt.Sprintf("SELECT sum(%s) AS ccu FROM %s %s AND %s IS NOT NULL FACET %s LIMIT %d",
+				computeUsageAttr, computeEventType, window, attr, attr, limit))
+			if err != nil {
+				return nil, fmt.Errorf("error querying %s CCU attribution for account %s: %w", kind, account.ID, err)
+			}
+
+			for _, result := range results {
+				name, ok := resultString(result, attr)
+				if !ok {
+					name, _ = resultString(result, "facet")
+				}
+				ccu, ok := resultFloat(result, "ccu")
+				if !ok {
+					continue
+				}
+
+				share := 0.0
+				if total > 0 {
+					share = ccu / total * 100.0
+				}
+
+				consumers = append(consumers, ComputeConsumer{
+					AccountID: account.ID,
+					Kind:      kind,
+					Name:      name,
+					CCU:       ccu,
+					Cost:      ccu * price,
+					SharePct:  share,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return consumers[i].CCU > consumers[j].CCU
+	})
+
+	return consumers, nil
+}
+
+// GetComputeAttributionReport builds a section of the top query CCU consumers, flagging any single
+// user or dashboard that drives at least runawayPct of an account's query CCUs
+func (nr *NewRelicProvider) GetComputeAttributionReport(start, end time.Time, limit int, runawayPct float64) (providers.Section, error) {
+	section := providers.Section{ID: SectionCompute, Title: "Compute Consumption Attribution", Order: 200}
+
+	consumers, err := nr.GetComputeConsumers(start, end, limit)
+	if err != nil {
+		return section, err
+	}
+
+	if len(consumers) == 0 {
+		return section, nil
+	}
+
+	section.AddSummary("Period Start", start, providers.ColumnDate, "")
+	section.AddSummary("Period End", end, providers.ColumnDate, "")
+	section.AddSummary("Price per CCU", ccuPrice(), providers.ColumnCurrency, "USD")
+
+	table := providers.SectionTable{
+		Title: "Query CCU Consumption by User and Dashboard",
+		Columns: []providers.SectionColumn{
+			{Key: "account_id", Title: "Account", Type: providers.ColumnString},
+			{Key: "kind", Title: "Kind", Type: providers.ColumnString},
+			{Key: "name", Title: "Name", Type: providers.ColumnString},
+			{Key: "ccu", Title: "CCU", Type: providers.ColumnNumber, Unit: "CCU"},
+			{Key: "cost", Title: "Cost", Type: providers.ColumnCurrency, Unit: "USD"},
+			{Key: "share", Title: "Share", Type: providers.ColumnPercent},
+			{Key: "flag", Title: "Flag", Type: providers.ColumnString},
+		},
+	}
+
+	for _, consumer := range consumers {
+		flag := ""
+		if runawayPct > 0 && consumer.SharePct >= runawayPct {
+			flag = "RUNAWAY"
+			section.Findings = append(section.Findings, providers.Finding{
+				Severity: providers.SeverityWarning,
+				Message:  fmt.Sprintf("%s %s used %.1f%% of the query CCUs of account %s", consumer.Kind, consumer.Name, consumer.SharePct, consumer.AccountID),
+				Detail:   fmt.Sprintf("Review its queries and refresh intervals; the runaway threshold is %.0f%%", runawayPct),
+				Impact:   consumer.Cost,
+				Currency: "USD",
+			})
+		}
+
+		table.AddRow(consumer.AccountID, consumer.Kind, consumer.Name, consumer.CCU, consumer.Cost, consumer.SharePct/100, flag)
+	}
+	section.Tables = append(section.Tables, table)
+
+	return section, nil
+}
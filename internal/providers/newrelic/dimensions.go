@@ -0,0 +1,112 @@
+package newrelic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// appIngestEventTypes are the APM event types whose ingest is attributed to an app
+const appIngestEventTypes = "Transaction, TransactionError, Span"
+
+// GetDimensionUsage breaks usage down by APM app (GB ingested per account) or by user
+// group (license users per group)
+func (nr *NewRelicProvider) GetDimensionUsage(dimension string, start, end time.Time) ([]providers.UsageData, error) {
+	switch dimension {
+	case providers.DimensionApp:
+		return nr.getAppIngest(start, end.AddDate(0, 0, 1))
+	case providers.DimensionUserGroup:
+		return nr.getUserGroupLicenses()
+	default:
+		return nil, fmt.Errorf("New Relic cannot break usage down by %s (use %s or %s)", dimension, providers.DimensionApp, providers.DimensionUserGroup)
+	}
+}
+
+// getAppIngest returns the estimated GB each app ingested in every allowed account
+func (nr *NewRelicProvider) getAppIngest(start, end time.Time) ([]providers.UsageData, error) {
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []providers.UsageData
+	for _, account := range accounts {
+		nrql := fmt.Sprintf("SELECT bytecountestimate() / 1e9 AS gb FROM %s SINCE '%s' UNTIL '%s' FACET appName LIMIT MAX",
+			appIngestEventTypes, start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+		results, err := nr.runNRQL(account.ID, nrql)
+		if err != nil {
+			return nil, fmt.Errorf("error querying app ingest for account %s: %w", account.ID, err)
+		}
+
+		for _, result := range results {
+			app, ok := resultString(result, "appName")
+			if !ok {
+				app, _ = resultString(result, "facet")
+			}
+			gb, ok := resultFloat(result, "gb")
+			if !ok || gb == 0 {
+				continue
+			}
+
+			usage = append(usage, providers.UsageData{
+				Service:   "APM",
+				Metric:    "Ingest",
+				Value:     gb,
+				Unit:      "GB",
+				Timestamp: start,
+				Metadata: map[string]interface{}{
+					"accountId":            account.ID,
+					"accountName":          account.Name,
+					providers.DimensionApp: app,
+				},
+			})
+		}
+	}
+
+	return usage, nil
+}
+
+// getUserGroupLicenses returns the license users of each group by license type. A user
+// in several groups counts as an equal fraction in each, so the users add up.
+func (nr *NewRelicProvider) getUserGroupLicenses() ([]providers.UsageData, error) {
+	userLicenses, err := nr.GetDetailedLicenseData()
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := nr.getGroupMemberships()
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ group, licenseType string }
+	users := make(map[key]float64)
+	for _, user := range userLicenses {
+		groups := memberships[user.UserID]
+		if len(groups) == 0 {
+			// Users outside every group still count, so their share stays unallocated
+			groups = []string{""}
+		}
+		for _, group := range groups {
+			users[key{group, user.LicenseType}] += 1 / float64(len(groups))
+		}
+	}
+
+	now := time.Now()
+	usage := make([]providers.UsageData, 0, len(users))
+	for k, count := range users {
+		usage = append(usage, providers.UsageData{
+			Service:   "Licenses",
+			Metric:    k.licenseType + " Licenses",
+			Value:     count,
+			Unit:      "Users",
+			Timestamp: now,
+			Metadata: map[string]interface{}{
+				providers.DimensionUserGroup: k.group,
+			},
+		})
+	}
+
+	return usage, nil
+}
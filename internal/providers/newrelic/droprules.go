@@ -0,0 +1,369 @@
+package newrelic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Drop rule actions supported by nrqlDropRulesCreate
+const (
+	DropActionData       = "DROP_DATA"
+	DropActionAttributes = "DROP_ATTRIBUTES"
+)
+
+// DropRuleOptions controls how drop rule proposals are generated
+type DropRuleOptions struct {
+	LookbackDays        int      // Days of data to sample when estimating volume
+	MaxEventTypes       int      // Number of noisiest event types to inspect for large attributes
+	MaxAttributes       int      // Maximum attribute proposals per event type
+	MinGBPerMonth       float64  // Proposals below this estimated volume are discarded
+	NoisyLogLevels      []string // Log levels that are candidates for dropping entirely
+	ProtectedAttributes []string // Attributes that are never proposed for dropping
+}
+
+// DefaultDropRuleOptions returns the options used when none are configured
+func DefaultDropRuleOptions() DropRuleOptions {
+	return DropRuleOptions{
+		LookbackDays:   7,
+		MaxEventTypes:  5,
+		MaxAttributes:  3,
+		MinGBPerMonth:  1,
+		NoisyLogLevels: []string{"debug", "trace"},
+		ProtectedAttributes: []string{
+			"timestamp", "message", "level", "entity.guid", "entity.name",
+			"appName", "host", "hostname", "trace.id", "span.id", "service.name",
+		},
+	}
+}
+
+// DropRuleProposal is a candidate NRQL drop filter with its estimated savings
+type DropRuleProposal struct {
+	ID             string  `json:"id"` // Stable across runs, see DropRuleID
+	AccountID      string  `json:"accountId"`
+	AccountName    string  `json:"accountName"`
+	Action         string  `json:"action"`
+	EventType      string  `json:"eventType"`
+	Attribute      string  `json:"attribute,omitempty"`
+	NRQL           string  `json:"nrql"`
+	Description    string  `json:"description"`
+	GBPerMonth     float64 `json:"estimatedGbPerMonth"`
+	MonthlySavings float64 `json:"estimatedMonthlySavings"`
+	Currency       string  `json:"currency"`
+}
+
+// DropRuleResult records the outcome of applying a single proposal
+type DropRuleResult struct {
+	Proposal DropRuleProposal `json:"proposal"`
+	RuleID   string           `json:"ruleId,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// DropRuleID identifies a drop rule by its account, action and NRQL, so a proposal
+// keeps its ID between the dry run and --apply however the list is ranked
+func DropRuleID(accountID, action, nrql string) string {
+	sum := sha256.Sum256([]byte(accountID + "\x00" + action + "\x00" + nrql))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Mutation renders the nrqlDropRulesCreate mutation that would create this rule
+func (p DropRuleProposal) Mutation() string {
+	nrql, _ := json.Marshal(p.NRQL)
+	description, _ := json.Marshal(p.Description)
+
+	return fmt.Sprintf(`mutation {
+  nrqlDropRulesCreate(accountId: %s, rules: [{action: %s, nrql: %s, description: %s}]) {
+    successes { id nrql }
+    failures { submitted { nrql } error { reason description } }
+  }
+}`, p.AccountID, p.Action, nrql, description)
+}
+
+// ProposeDropRules inspects the noisiest event types and attributes in each allowed
+// account and returns candidate drop rules sorted by estimated savings
+func (nr *NewRelicProvider) ProposeDropRules(opts DropRuleOptions) ([]DropRuleProposal, error) {
+	if opts.LookbackDays <= 0 {
+		opts.LookbackDays = DefaultDropRuleOptions().LookbackDays
+	}
+
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	price := nr.dataPricePerGB()
+	monthScale := 30.0 / float64(opts.LookbackDays)
+	since := fmt.Sprintf("SINCE %d days ago", opts.LookbackDays)
+
+	protected := make(map[string]bool, len(opts.ProtectedAttributes))
+	for _, attr := range opts.ProtectedAttributes {
+		protected[attr] = true
+	}
+
+	var proposals []DropRuleProposal
+	for _, account := range accounts {
+		newProposal := func(action, eventType, attribute, nrql, description string, gb float64) DropRuleProposal {
+			return DropRuleProposal{
+				ID:             DropRuleID(account.ID, action, nrql),
+				AccountID:      account.ID,
+				AccountName:    account.Name,
+				Action:         action,
+				EventType:      eventType,
+				Attribute:      attribute,
+				NRQL:           nrql,
+				Description:    description,
+				GBPerMonth:     gb * monthScale,
+				MonthlySavings: gb * monthScale * price,
+				Currency:       "USD",
+			}
+		}
+
+		// Rank event types by estimated ingest volume
+		eventTypes, err := nr.eventTypeVolumes(account.ID, since)
+		if err != nil {
+			slog.Warn("skipping drop rule analysis", "account", account.ID, "error", err)
+			continue
+		}
+
+		// Noisy log levels are the most common and safest thing to drop
+		if _, ok := eventTypes["Log"]; ok && len(opts.NoisyLogLevels) > 0 {
+			results, err := nr.runNRQL(account.ID, fmt.Sprintf("SELECT bytecountestimate()/1e9 AS gb FROM Log %s FACET level LIMIT MAX", since))
+			if err != nil {
+				slog.Warn("could not estimate log volume by level", "account", account.ID, "error", err)
+			}
+			for _, result := range results {
+				level, _ := resultString(result, "level")
+				gb, ok := resultFloat(result, "gb")
+				if !ok || !containsFold(opts.NoisyLogLevels, level) {
+					continue
+				}
+				nrql := fmt.Sprintf("SELECT * FROM Log WHERE level = %s", nrqlString(level))
+				proposals = append(proposals, newProposal(DropActionData, "Log", "", nrql,
+					fmt.Sprintf("Drop %s level logs", level), gb))
+			}
+		}
+
+		// Look for large string attributes on the noisiest event types
+		for _, eventType := range topEventTypes(eventTypes, opts.MaxEventTypes) {
+			attributes, err := nr.attributeVolumes(account.ID, eventType, since, protected)
+			if err != nil {
+				slog.Warn("could not estimate attribute volume", "eventType", eventType, "account", account.ID, "error", err)
+				continue
+			}
+
+			for i, attr := range attributes {
+				if i >= opts.MaxAttributes {
+					break
+				}
+				nrql := fmt.Sprintf("SELECT %s FROM %s", nrqlIdentifier(attr.name), nrqlIdentifier(eventType))
+				proposals = append(proposals, newProposal(DropActionAttributes, eventType, attr.name, nrql,
+					fmt.Sprintf("Drop attribute %s from %s", attr.name, eventType), attr.gb))
+			}
+		}
+	}
+
+	// Discard proposals that would not meaningfully move the bill
+	filtered := proposals[:0]
+	for _, proposal := range proposals {
+		if proposal.GBPerMonth >= opts.MinGBPerMonth {
+			filtered = append(filtered, proposal)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].MonthlySavings > filtered[j].MonthlySavings
+	})
+
+	return filtered, nil
+}
+
+// ApplyDropRules creates the proposed drop rules in their accounts
+func (nr *NewRelicProvider) ApplyDropRules(proposals []DropRuleProposal) ([]DropRuleResult, error) {
+	results := make([]DropRuleResult, 0, len(proposals))
+
+	for _, proposal := range proposals {
+		result := DropRuleResult{Proposal: proposal}
+
+		resp, err := nr.client.NerdGraph.Query(proposal.Mutation(), nil)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		var response struct {
+			NrqlDropRulesCreate struct {
+				Successes []struct {
+					ID string `json:"id"`
+				} `json:"successes"`
+				Failures []struct {
+					Error struct {
+						Reason      string `json:"reason"`
+						Description string `json:"description"`
+					} `json:"error"`
+				} `json:"failures"`
+			} `json:"nrqlDropRulesCreate"`
+		}
+
+		jsonData, err := json.Marshal(resp)
+		if err != nil {
+			return results, fmt.Errorf("error marshalling drop rule response: %w", err)
+		}
+		if err := json.Unmarshal(jsonData, &response); err != nil {
+			return results, fmt.Errorf("error unmarshalling drop rule response: %w", err)
+		}
+
+		created := response.NrqlDropRulesCreate
+		switch {
+		case len(created.Successes) > 0:
+			result.RuleID = created.Successes[0].ID
+		case len(created.Failures) > 0:
+			result.Error = fmt.Sprintf("%s: %s", created.Failures[0].Error.Reason, created.Failures[0].Error.Description)
+		default:
+			result.Error = "no result returned for drop rule"
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// eventTypeVolumes returns the estimated GB ingested per event type over the window
+func (nr *NewRelicProvider) eventTypeVolumes(accountID, since string) (map[string]float64, error) {
+	results, err := nr.runNRQL(accountID, fmt.Sprintf("SHOW EVENT TYPES %s", since))
+	if err != nil {
+		return nil, err
+	}
+
+	var eventTypes []string
+	for _, result := range results {
+		list, ok := result["eventTypes"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			// New Relic's own accounting events are not billable ingest
+			if !ok || strings.HasPrefix(name, "Nr") {
+				continue
+			}
+			eventTypes = append(eventTypes, nrqlIdentifier(name))
+		}
+	}
+
+	volumes := make(map[string]float64)
+	if len(eventTypes) == 0 {
+		return volumes, nil
+	}
+
+	results, err = nr.runNRQL(accountID, fmt.Sprintf("SELECT bytecountestimate()/1e9 AS gb FROM %s %s FACET eventType() LIMIT MAX",
+		strings.Join(eventTypes, ", "), since))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		name, ok := resultString(result, "eventType")
+		if !ok {
+			continue
+		}
+		if gb, ok := resultFloat(result, "gb"); ok {
+			volumes[name] = gb
+		}
+	}
+
+	return volumes, nil
+}
+
+type attributeVolume struct {
+	name string
+	gb   float64
+}
+
+// attributeVolumes estimates the GB contributed by each string attribute of an event type,
+// largest first
+func (nr *NewRelicProvider) attributeVolumes(accountID, eventType, since string, protected map[string]bool) ([]attributeVolume, error) {
+	results, err := nr.runNRQL(accountID, fmt.Sprintf("SELECT keyset() FROM %s %s", nrqlIdentifier(eventType), since))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, result := range results {
+		list, ok := result["stringKeys"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			if key, ok := item.(string); ok && !protected[key] {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	// Sum the length of every string attribute in a single query
+	selects := make([]string, 0, len(keys))
+	for i, key := range keys {
+		selects = append(selects, fmt.Sprintf("sum(length(%s)) AS 'a%d'", nrqlIdentifier(key), i))
+	}
+
+	results, err = nr.runNRQL(accountID, fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(selects, ", "), nrqlIdentifier(eventType), since))
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []attributeVolume
+	for _, result := range results {
+		for i, key := range keys {
+			if bytes, ok := resultFloat(result, "a"+strconv.Itoa(i)); ok && bytes > 0 {
+				volumes = append(volumes, attributeVolume{name: key, gb: bytes / 1e9})
+			}
+		}
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].gb > volumes[j].gb
+	})
+
+	return volumes, nil
+}
+
+// topEventTypes returns up to limit event types ordered by volume, largest first
+func topEventTypes(volumes map[string]float64, limit int) []string {
+	names := make([]string, 0, len(volumes))
+	for name := range volumes {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if volumes[names[i]] != volumes[names[j]] {
+			return volumes[names[i]] > volumes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	return names
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
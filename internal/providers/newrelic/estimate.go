@@ -0,0 +1,46 @@
+package newrelic
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// EstimateCostData estimates New Relic cost over the period from the data ingested and
+// the licenses in use, priced with the pricing catalog
+func (nr *NewRelicProvider) EstimateCostData(start, end time.Time) ([]providers.CostData, error) {
+	dataCosts, err := nr.estimateDataCostData(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	licenseCosts, err := nr.getLicenseCostData(start, end)
+	if err != nil {
+		slog.Warn("failed to get license cost data", "error", err)
+		return dataCosts, nil
+	}
+	return append(dataCosts, licenseCosts...), nil
+}
+
+// estimateDataCostData prices the GB ingested per account and product line at the
+// data option's catalog price, honoring newrelic.pricing.data_per_gb overrides
+func (nr *NewRelicProvider) estimateDataCostData(start, end time.Time) ([]providers.CostData, error) {
+	usage, err := nr.getDataMetrics(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := nr.pricingCatalog()
+	if override := viper.GetFloat64("newrelic.pricing.data_per_gb"); override > 0 {
+		if p, ok := catalog.Lookup("newrelic", "", dataOption(), pricing.AnyService, "DataSize"); ok {
+			p.Price = override
+			catalog = catalog.Merge(&pricing.Catalog{Prices: []pricing.Price{p}})
+		}
+	}
+
+	scope := pricing.Scope{Provider: "newrelic", Option: dataOption()}
+	return catalog.Estimate(scope, usage, pricing.PeriodMonthly, start, end), nil
+}
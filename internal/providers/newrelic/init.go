@@ -1,10 +1,29 @@
 package newrelic
 
-import "github.com/ilhicas/observability-cost-center/internal/providers"
+import (
+	"fmt"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/ilhicas/observability-cost-center/internal/recommend"
+)
 
 func init() {
 	// Register New Relic provider factory
 	providers.RegisterProvider("newrelic", func() (providers.Provider, error) {
 		return NewProvider()
 	})
+
+	// Register the savings recommendation rules, in the order they are evaluated
+	recommend.RegisterRule(recommend.Rule{
+		ID:          RuleInactiveLicenses,
+		Provider:    "newrelic",
+		Description: "Reclaim the paid licenses of users inactive for newrelic.inactive_days (default 30)",
+		Evaluate: func(provider providers.Provider) ([]recommend.Recommendation, error) {
+			nr, ok := provider.(*NewRelicProvider)
+			if !ok {
+				return nil, fmt.Errorf("rule %s needs the New Relic provider, got %s", RuleInactiveLicenses, provider.GetName())
+			}
+			return nr.inactiveLicenseRecommendations()
+		},
+	})
 }
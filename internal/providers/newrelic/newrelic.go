@@ -3,11 +3,13 @@ package newrelic
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
 	"github.com/ilhicas/observability-cost-center/internal/providers"
 	"github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/spf13/viper"
@@ -15,9 +17,20 @@ import (
 
 // NewRelicProvider implements the Provider interface for New Relic
 type NewRelicProvider struct {
-	client *newrelic.NewRelic
+	client             *newrelic.NewRelic
+	accountFilter      AccountFilter
+	licenseAttribution string
+	accounts           []AccountInfo
+	catalog            *pricing.Catalog
 }
 
+// Identifiers of the report sections contributed by the New Relic provider
+const (
+	SectionLicenses  = "newrelic.licenses"
+	SectionCompute   = "newrelic.compute"
+	SectionRetention = "newrelic.retention"
+)
+
 // LicenseInfo represents New Relic license information
 type LicenseInfo struct {
 	Type           string  `json:"type"`
@@ -28,13 +41,15 @@ type LicenseInfo struct {
 
 // UserLicenseData represents detailed license information for a single user
 type UserLicenseData struct {
-	UserID      string    `json:"userId"`
-	UserName    string    `json:"userName"`
-	Email       string    `json:"email"`
-	LicenseType string    `json:"licenseType"`
-	LastActive  time.Time `json:"lastActive"`
-	IsActive    bool      `json:"isActive"`
-	Cost        float64   `json:"cost"` // Cost of this license
+	UserID      string             `json:"userId"`
+	UserName    string             `json:"userName"`
+	Email       string             `json:"email"`
+	LicenseType string             `json:"licenseType"`
+	LastActive  time.Time          `json:"lastActive"`
+	IsActive    bool               `json:"isActive"`
+	Cost        float64            `json:"cost"` // Cost of this license
+	Groups      []string           `json:"groups,omitempty"`
+	Teams       map[string]float64 `json:"teams,omitempty"` // Share of the license owned by each team
 }
 
 // NewProvider creates a new New Relic provider
@@ -77,7 +92,21 @@ func NewProvider() (*NewRelicProvider, error) {
 		return nil, fmt.Errorf("error creating New Relic client: %w", err)
 	}
 
-	return &NewRelicProvider{client: client}, nil
+	// Decide where organization-level license cost ends up
+	licenseAttribution := strings.ToLower(viper.GetString("newrelic.license_attribution"))
+	switch licenseAttribution {
+	case "":
+		licenseAttribution = LicenseAttributionOrganization
+	case LicenseAttributionOrganization, LicenseAttributionAccount, LicenseAttributionNone:
+	default:
+		return nil, fmt.Errorf("unsupported newrelic.license_attribution %q (use organization, account or none)", licenseAttribution)
+	}
+
+	return &NewRelicProvider{
+		client:             client,
+		accountFilter:      NewAccountFilterFromConfig(viper.GetViper()),
+		licenseAttribution: licenseAttribution,
+	}, nil
 }
 
 // GetName returns the provider name
@@ -93,67 +122,41 @@ func (nr *NewRelicProvider) GetUsageData(start, end time.Time) ([]providers.Usag
 		return nil, err
 	}
 
+	combinedUsage := dataMetrics
+
 	// Get license usage data - always include license usage for New Relic
 	licenseUsage, err := nr.getLicenseUsageData()
 	if err != nil {
-		// Log the error but continue with data metrics
-		fmt.Printf("Warning: Failed to get license usage data: %v\n", err)
-		return dataMetrics, nil
+		// Log the error but continue with the other usage data
+		slog.Warn("failed to get license usage data", "error", err)
+	} else {
+		combinedUsage = append(combinedUsage, licenseUsage...)
 	}
 
-	// Combine both types of usage data
-	combinedUsage := append(dataMetrics, licenseUsage...)
-	return combinedUsage, nil
-}
-
-// getDataMetrics retrieves usage data metrics from New Relic using NerdGraph API
-func (nr *NewRelicProvider) getDataMetrics(start, end time.Time) ([]providers.UsageData, error) {
-	// First get all account IDs
-	accountsQuery := `{
-		actor {
-			accounts {
-				id
-				name
-			}
-		}
-	}`
-
-	// Execute the query to get all account IDs
-	accountsResp, err := nr.client.NerdGraph.Query(accountsQuery, nil)
+	// Compute (CCU) consumption only exists for accounts on compute-based pricing
+	computeUsage, err := nr.getComputeUsageData(start, end)
 	if err != nil {
-		return nil, fmt.Errorf("error querying account IDs: %w", err)
-	}
-
-	// Manually unmarshal the response with the corrected type for id
-	var accountsResponse struct {
-		Actor struct {
-			Accounts []struct {
-				ID   json.Number `json:"id"` // Change from string to json.Number
-				Name string      `json:"name"`
-			} `json:"accounts"`
-		} `json:"actor"`
+		slog.Warn("failed to get compute usage data", "error", err)
+		return combinedUsage, nil
 	}
 
-	jsonData, err := json.Marshal(accountsResp)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling account response: %w", err)
-	}
+	return append(combinedUsage, computeUsage...), nil
+}
 
-	err = json.Unmarshal(jsonData, &accountsResponse)
+// getDataMetrics retrieves usage data metrics from New Relic using NerdGraph API
+func (nr *NewRelicProvider) getDataMetrics(start, end time.Time) ([]providers.UsageData, error) {
+	// Get the accounts allowed by the account filter
+	accounts, err := nr.listAccounts()
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling account response: %w", err)
+		return nil, err
 	}
 
-	// Debug the response
-	fmt.Printf("Found %d accounts\n", len(accountsResponse.Actor.Accounts))
-
 	var allUsageData []providers.UsageData
 
 	// For each account, query the data usage
-	for _, account := range accountsResponse.Actor.Accounts {
-		// Convert ID to string when using it
-		accountID := account.ID.String()
-		fmt.Printf("Querying usage for account %s (%s)\n", accountID, account.Name)
+	for _, account := range accounts {
+		accountID := account.ID
+		slog.Debug("querying usage", "account", accountID, "accountName", account.Name)
 
 		// Query for data usage metrics - Use accountID instead of account.ID
 		dataQuery := fmt.Sprintf(`{
@@ -172,11 +175,6 @@ func (nr *NewRelicProvider) getDataMetrics(start, end time.Time) ([]providers.Us
 			return nil, fmt.Errorf("error querying data metrics for account %s: %w", accountID, err)
 		}
 
-		// Debug the raw response
-		fmt.Printf("Raw response type: %T\n", dataResp)
-		respBytes, _ := json.Marshal(dataResp)
-		fmt.Printf("Raw response: %s\n", string(respBytes))
-
 		// Manually unmarshal the response
 		var dataResponse struct {
 			Actor struct {
@@ -200,26 +198,24 @@ func (nr *NewRelicProvider) getDataMetrics(start, end time.Time) ([]providers.Us
 
 		// Check if we have valid results
 		if dataResponse.Actor.Account.NRQL.Results == nil {
-			fmt.Printf("Warning: No results found in response for account %s\n", accountID)
+			slog.Warn("no usage results found", "account", accountID)
 			continue
 		}
 
 		// Process the results
 		for _, result := range dataResponse.Actor.Account.NRQL.Results {
-			// Debug the result structure
-			resultBytes, _ := json.Marshal(result)
-			fmt.Printf("Result: %s\n", string(resultBytes))
+			slog.Debug("usage result", "account", accountID, "result", result)
 
 			productLine, ok := result["productLine"].(string)
 			if !ok {
-				fmt.Printf("Warning: Cannot extract productLine from result\n")
+				slog.Warn("cannot extract productLine from usage result", "account", accountID)
 				continue
 			}
 
 			sumKey := "sum.newRelicDbSize"
 			value, ok := result[sumKey].(float64)
 			if !ok {
-				fmt.Printf("Warning: Cannot extract %s as float64 from result\n", sumKey)
+				slog.Warn("cannot extract usage value from result", "key", sumKey, "account", accountID)
 				continue
 			}
 
@@ -239,7 +235,7 @@ func (nr *NewRelicProvider) getDataMetrics(start, end time.Time) ([]providers.Us
 
 	// If no real data was found, return empty slice instead of sample data
 	if len(allUsageData) == 0 {
-		fmt.Println("No usage data found for the specified period")
+		slog.Info("no usage data found for the specified period")
 		return []providers.UsageData{}, nil
 	}
 
@@ -273,73 +269,65 @@ func (nr *NewRelicProvider) getLicenseUsageData() ([]providers.UsageData, error)
 	return usageData, nil
 }
 
-// GetCostData retrieves cost data from New Relic
+// GetCostData retrieves cost data from New Relic. Depending on newrelic.cost_source,
+// data cost is estimated from usage when NrConsumption has none.
 func (nr *NewRelicProvider) GetCostData(start, end time.Time) ([]providers.CostData, error) {
+	source, err := providers.ParseCostSource(viper.GetString("newrelic.cost_source"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid newrelic.cost_source: %w", err)
+	}
+	if source == providers.CostSourceEstimate {
+		return nr.EstimateCostData(start, end)
+	}
+
 	// Get basic cost data
 	basicCosts, err := nr.getBasicCostData(start, end)
+	if source == providers.CostSourceAuto && (err != nil || len(basicCosts) == 0) {
+		estimated, estimateErr := nr.estimateDataCostData(start, end)
+		if estimateErr == nil && len(estimated) > 0 {
+			slog.Warn("NrConsumption cost data unavailable, estimating data cost from usage with the pricing catalog", "error", err)
+			basicCosts, err = estimated, nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	combinedCosts := basicCosts
+
 	// Get license cost data - always include license costs for New Relic
 	licenseCosts, err := nr.getLicenseCostData(start, end)
 	if err != nil {
-		// Log the error but continue with basic cost data
-		fmt.Printf("Warning: Failed to get license cost data: %v\n", err)
-		return basicCosts, nil
+		// Log the error but continue with the other cost data
+		slog.Warn("failed to get license cost data", "error", err)
+	} else {
+		combinedCosts = append(combinedCosts, licenseCosts...)
 	}
 
-	// Combine both types of cost data
-	combinedCosts := append(basicCosts, licenseCosts...)
-	return combinedCosts, nil
-}
-
-// getBasicCostData retrieves standard cost metrics
-func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.CostData, error) {
-	// First get all account IDs
-	accountsQuery := `{
-        actor {
-            accounts {
-                id
-                name
-            }
-        }
-    }`
-
-	// Execute the query to get all account IDs
-	accountsResp, err := nr.client.NerdGraph.Query(accountsQuery, nil)
+	// Add compute (CCU) cost per account, day and capability
+	computeCosts, err := nr.getComputeCostData(start, end)
 	if err != nil {
-		return nil, fmt.Errorf("error querying account IDs: %w", err)
-	}
-
-	// Manually unmarshal the response with the corrected type for id
-	var accountsResponse struct {
-		Actor struct {
-			Accounts []struct {
-				ID   json.Number `json:"id"`
-				Name string      `json:"name"`
-			} `json:"accounts"`
-		} `json:"actor"`
+		slog.Warn("failed to get compute cost data", "error", err)
+		return combinedCosts, nil
 	}
 
-	jsonData, err := json.Marshal(accountsResp)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling account response: %w", err)
-	}
+	return append(combinedCosts, computeCosts...), nil
+}
 
-	err = json.Unmarshal(jsonData, &accountsResponse)
+// getBasicCostData retrieves standard cost metrics
+func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.CostData, error) {
+	// Get the accounts allowed by the account filter
+	accounts, err := nr.listAccounts()
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling account response: %w", err)
+		return nil, err
 	}
 
-	fmt.Printf("Found %d accounts for cost data\n", len(accountsResponse.Actor.Accounts))
-
 	var allCostData []providers.CostData
 
 	// For each account, query the cost data
-	for _, account := range accountsResponse.Actor.Accounts {
-		accountID := account.ID.String()
-		fmt.Printf("Querying cost data for account %s (%s)\n", accountID, account.Name)
+	for _, account := range accounts {
+		accountID := account.ID
+		slog.Debug("querying cost data", "account", accountID, "accountName", account.Name)
 
 		// Query for billing data using NerdGraph
 		costQuery := fmt.Sprintf(`{
@@ -358,11 +346,6 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 			return nil, fmt.Errorf("error querying cost data for account %s: %w", accountID, err)
 		}
 
-		// Debug the raw response
-		fmt.Printf("Cost data raw response type: %T\n", costResp)
-		respBytes, _ := json.Marshal(costResp)
-		fmt.Printf("Cost data raw response: %s\n", string(respBytes))
-
 		// Unmarshal the cost data response
 		var costResponse struct {
 			Actor struct {
@@ -386,7 +369,7 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 
 		// Check if we have valid results
 		if costResponse.Actor.Account.NRQL.Results == nil {
-			fmt.Printf("Warning: No cost results found in response for account %s\n", accountID)
+			slog.Warn("no cost results found", "account", accountID)
 			continue
 		}
 
@@ -395,7 +378,7 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 			// Extract productLine
 			productLine, ok := result["productLine"].(string)
 			if !ok {
-				fmt.Printf("Warning: Cannot extract productLine from cost result\n")
+				slog.Warn("cannot extract productLine from cost result", "account", accountID)
 				continue
 			}
 
@@ -408,7 +391,7 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 			// Extract cost
 			cost, ok := result["cost"].(float64)
 			if !ok {
-				fmt.Printf("Warning: Cannot extract cost as float64 from result\n")
+				slog.Warn("cannot extract cost from result", "account", accountID)
 				continue
 			}
 
@@ -435,7 +418,7 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 
 	// If no cost data was found, return empty slice
 	if len(allCostData) == 0 {
-		fmt.Println("No cost data found for the specified period")
+		slog.Info("no cost data found for the specified period")
 	}
 
 	return allCostData, nil
@@ -443,48 +426,13 @@ func (nr *NewRelicProvider) getBasicCostData(start, end time.Time) ([]providers.
 
 // getLicenseCostData retrieves cost data related to licenses
 func (nr *NewRelicProvider) getLicenseCostData(start, end time.Time) ([]providers.CostData, error) {
-	// First get all account IDs to associate licenses with accounts
-	accountsQuery := `{
-		actor {
-			accounts {
-				id
-				name
-			}
-		}
-	}`
-
-	// Execute the query to get all account IDs
-	accountsResp, err := nr.client.NerdGraph.Query(accountsQuery, nil)
+	// Licenses belong to the organization, not to an individual account
+	accountID, ownerName, ok, err := nr.licenseAttributionTarget()
 	if err != nil {
-		return nil, fmt.Errorf("error querying account IDs for license costs: %w", err)
+		return nil, fmt.Errorf("error resolving license cost attribution: %w", err)
 	}
-
-	// Manually unmarshal the response with the corrected type for id
-	var accountsResponse struct {
-		Actor struct {
-			Accounts []struct {
-				ID   json.Number `json:"id"`
-				Name string      `json:"name"`
-			} `json:"accounts"`
-		} `json:"actor"`
-	}
-
-	jsonData, err := json.Marshal(accountsResp)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling account response: %w", err)
-	}
-
-	err = json.Unmarshal(jsonData, &accountsResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling account response: %w", err)
-	}
-
-	// Get real account ID or use "unknown" if no accounts found
-	accountID := "unknown"
-	accountName := "Unknown Account"
-	if len(accountsResponse.Actor.Accounts) > 0 {
-		accountID = accountsResponse.Actor.Accounts[0].ID.String()
-		accountName = accountsResponse.Actor.Accounts[0].Name
+	if !ok {
+		return []providers.CostData{}, nil
 	}
 
 	// Get license information
@@ -493,23 +441,10 @@ func (nr *NewRelicProvider) getLicenseCostData(start, end time.Time) ([]provider
 		return nil, fmt.Errorf("failed to get license info: %w", err)
 	}
 
-	// Define cost per license type (this would normally come from billing API)
-	licenseCosts := map[string]float64{
-		"User":          99.00,  // $99 per user license
-		"Full platform": 199.00, // $199 per full license
-		"Basic":         49.00,  // $49 per basic license
-		"Core":          499.00, // $499 per core license
-		"LimitedAccess": 29.00,  // $29 per limited access license
-	}
-
 	// Convert license information to cost data format
 	costData := make([]providers.CostData, 0, len(licenseInfo))
 	for _, license := range licenseInfo {
-		// Get cost per license, default to 0 if not found
-		costPerLicense, ok := licenseCosts[license.Type]
-		if !ok {
-			costPerLicense = 0
-		}
+		costPerLicense := nr.licensePrice(license.Type)
 
 		costData = append(costData, providers.CostData{
 			Service:     "Licenses",
@@ -522,7 +457,8 @@ func (nr *NewRelicProvider) getLicenseCostData(start, end time.Time) ([]provider
 			StartTime:   start,
 			EndTime:     end,
 			AccountID:   accountID,
-			Description: fmt.Sprintf("%s Licenses (%d/%d used) - Account: %s", license.Type, license.UsedLicenses, license.TotalLicenses, accountName),
+			Description: fmt.Sprintf("%s Licenses (%d/%d used) - %s", license.Type, license.UsedLicenses, license.TotalLicenses, ownerName),
+			Estimated:   true, // List price; the billing API does not expose license cost
 		})
 	}
 
@@ -566,11 +502,6 @@ func (nr *NewRelicProvider) getAuthDomainIDs() ([]string, error) {
 		return nil, fmt.Errorf("error querying authentication domain IDs: %w", err)
 	}
 
-	// Debug the raw response
-	fmt.Printf("Auth Domains Raw Response Type: %T\n", resp)
-	respBytes, _ := json.Marshal(resp)
-	fmt.Printf("Auth Domains Raw Response: %s\n", string(respBytes))
-
 	// Define the structure with string for ID (UUIDs are strings, not numbers)
 	var response struct {
 		Actor struct {
@@ -602,7 +533,7 @@ func (nr *NewRelicProvider) getAuthDomainIDs() ([]string, error) {
 		domainIDs = append(domainIDs, domain.ID)
 	}
 
-	fmt.Printf("Found %d authentication domains\n", len(domainIDs))
+	slog.Debug("found authentication domains", "count", len(domainIDs))
 	return domainIDs, nil
 }
 
@@ -649,11 +580,6 @@ func (nr *NewRelicProvider) GetLicenseInfo() ([]LicenseInfo, error) {
 			return nil, fmt.Errorf("error querying users for domain %s: %w", domainID, err)
 		}
 
-		// Debug the raw response
-		fmt.Printf("Domain %s users raw response type: %T\n", domainID, resp)
-		respBytes, _ := json.Marshal(resp)
-		fmt.Printf("Domain %s users raw response: %s\n", domainID, string(respBytes))
-
 		var responseData struct {
 			Actor struct {
 				Organization struct {
@@ -693,14 +619,14 @@ func (nr *NewRelicProvider) GetLicenseInfo() ([]LicenseInfo, error) {
 		// Check if we got a valid response with authentication domains
 		authDomains := responseData.Actor.Organization.UserManagement.AuthenticationDomains.AuthenticationDomains
 		if len(authDomains) == 0 {
-			fmt.Printf("Warning: No authentication domains found for domain ID %s\n", domainID)
+			slog.Warn("no authentication domains found", "domain", domainID)
 			continue
 		}
 
 		// Extract and convert users to our NerdGraphUser type
 		for _, domain := range authDomains {
 			users := domain.Users.Users
-			fmt.Printf("Found %d users in domain %s\n", len(users), domainID)
+			slog.Debug("found users in domain", "count", len(users), "domain", domainID)
 
 			for _, user := range users {
 				allUsers = append(allUsers, NerdGraphUser{
@@ -717,7 +643,7 @@ func (nr *NewRelicProvider) GetLicenseInfo() ([]LicenseInfo, error) {
 		}
 	}
 
-	fmt.Printf("Total users found across all domains: %d\n", len(allUsers))
+	slog.Debug("total users found across all domains", "count", len(allUsers))
 
 	// Calculate license types and usage
 	licenseCount := map[string]struct {
@@ -785,61 +711,47 @@ func (nr *NewRelicProvider) GetLicenseInfo() ([]LicenseInfo, error) {
 	return result, nil
 }
 
-// GetLicenseUsageReport generates a detailed report of license usage including per-user details
-func (nr *NewRelicProvider) GetLicenseUsageReport(daysInactive int) (string, error) {
+// GetLicenseUsageReport builds a section on license usage including per-user details
+// and the savings from reclaiming inactive licenses
+func (nr *NewRelicProvider) GetLicenseUsageReport(daysInactive int) (providers.Section, error) {
+	section := providers.Section{ID: SectionLicenses, Title: "License Usage Details", Order: 100}
+
 	// Get detailed user license information
 	userLicenses, err := nr.GetDetailedLicenseData()
 	if err != nil {
-		return "", fmt.Errorf("error getting detailed license data: %w", err)
+		return section, fmt.Errorf("error getting detailed license data: %w", err)
 	}
 
-	// Define cost per license type (this would normally come from billing API)
-	licenseCosts := map[string]float64{
-		"User":          99.00,  // $99 per user license
-		"Full platform": 199.00, // $199 per full license
-		"Basic":         49.00,  // $49 per basic license
-		"Core":          499.00, // $499 per core license
-		"LimitedAccess": 29.00,  // $29 per limited access license
-	}
-
-	// Calculate inactive threshold
-	inactiveThreshold := time.Now().AddDate(0, 0, -daysInactive)
+	nr.markInactiveLicenses(userLicenses, daysInactive, time.Now())
 
 	// Process user data to identify inactive users and potential savings
 	var totalCost, potentialSavings float64
 	inactiveCount := 0
 	totalCount := len(userLicenses)
 
-	for i := range userLicenses {
-		// Assign cost to each license
-		cost, exists := licenseCosts[userLicenses[i].LicenseType]
-		if !exists {
-			cost = 0
-		}
-		userLicenses[i].Cost = cost
-		totalCost += cost
-
-		// Check if the user is inactive based on the threshold
-		if userLicenses[i].LastActive.Before(inactiveThreshold) {
-			userLicenses[i].IsActive = false
+	for _, user := range userLicenses {
+		totalCost += user.Cost
+		if !user.IsActive {
 			inactiveCount++
-			potentialSavings += cost
-		} else {
-			userLicenses[i].IsActive = true
+			potentialSavings += user.Cost
 		}
 	}
 
-	// Create the report
-	var report strings.Builder
-	report.WriteString(fmt.Sprintf("License Usage Report for New Relic\n"))
-	report.WriteString(fmt.Sprintf("Date: %s\n\n", time.Now().Format("2006-01-02")))
+	section.AddSummary("Total Users", totalCount, providers.ColumnInteger, "")
+	section.AddSummary("Active Users", totalCount-inactiveCount, providers.ColumnInteger, "")
+	section.AddSummary(fmt.Sprintf("Inactive Users (>%d days)", daysInactive), inactiveCount, providers.ColumnInteger, "")
+	section.AddSummary("Total License Cost", totalCost, providers.ColumnCurrency, "USD")
+	section.AddSummary("Potential Monthly Savings", potentialSavings, providers.ColumnCurrency, "USD")
 
-	report.WriteString(fmt.Sprintf("Summary:\n"))
-	report.WriteString(fmt.Sprintf("  Total Users: %d\n", totalCount))
-	report.WriteString(fmt.Sprintf("  Active Users: %d\n", totalCount-inactiveCount))
-	report.WriteString(fmt.Sprintf("  Inactive Users (>%d days): %d\n", daysInactive, inactiveCount))
-	report.WriteString(fmt.Sprintf("  Total License Cost: $%.2f\n", totalCost))
-	report.WriteString(fmt.Sprintf("  Potential Monthly Savings: $%.2f\n\n", potentialSavings))
+	if inactiveCount > 0 {
+		section.Findings = append(section.Findings, providers.Finding{
+			Severity: providers.SeverityWarning,
+			Message:  fmt.Sprintf("%d of %d users have not been active for more than %d days", inactiveCount, totalCount, daysInactive),
+			Detail:   "Downgrade or remove their licenses to stop paying for unused seats; the recommend command lists each license to reclaim",
+			Impact:   potentialSavings,
+			Currency: "USD",
+		})
+	}
 
 	// License type breakdown
 	licenseTypeCounts := make(map[string]int)
@@ -852,22 +764,50 @@ func (nr *NewRelicProvider) GetLicenseUsageReport(daysInactive int) (string, err
 		}
 	}
 
-	report.WriteString("License Type Breakdown:\n")
-	report.WriteString("TYPE            | TOTAL | INACTIVE | COST PER LICENSE | POTENTIAL SAVINGS\n")
-	report.WriteString("----------------+-------+----------+-----------------+------------------\n")
+	licenseTypes := make([]string, 0, len(licenseTypeCounts))
+	for licType := range licenseTypeCounts {
+		licenseTypes = append(licenseTypes, licType)
+	}
+	sort.Strings(licenseTypes)
 
-	for licType, count := range licenseTypeCounts {
-		cost := licenseCosts[licType]
+	breakdown := providers.SectionTable{
+		Title: "License Type Breakdown",
+		Columns: []providers.SectionColumn{
+			{Key: "license_type", Title: "Type", Type: providers.ColumnString},
+			{Key: "total", Title: "Total", Type: providers.ColumnInteger},
+			{Key: "inactive", Title: "Inactive", Type: providers.ColumnInteger},
+			{Key: "cost_per_license", Title: "Cost per License", Type: providers.ColumnCurrency, Unit: "USD"},
+			{Key: "potential_savings", Title: "Potential Savings", Type: providers.ColumnCurrency, Unit: "USD"},
+		},
+	}
+	for _, licType := range licenseTypes {
+		cost := nr.licensePrice(licType)
 		inactive := licenseTypeInactiveCounts[licType]
-		savings := float64(inactive) * cost
-		report.WriteString(fmt.Sprintf("%-16s| %-5d | %-8d | $%-15.2f| $%.2f\n", licType, count, inactive, cost, savings))
+		breakdown.AddRow(licType, licenseTypeCounts[licType], inactive, cost, float64(inactive)*cost)
 	}
-	report.WriteString("\n")
+	section.Tables = append(section.Tables, breakdown)
 
-	// Detailed user breakdown
-	report.WriteString("Detailed License Usage:\n")
-	report.WriteString("USERNAME        | EMAIL                  | LICENSE TYPE    | LAST ACTIVE         | STATUS   | COST\n")
-	report.WriteString("----------------+------------------------+----------------+---------------------+----------+--------\n")
+	// Cost per team when NerdGraph groups are mapped to teams
+	if mapping, ok := TeamMappingFromConfig(viper.GetViper()); ok {
+		if err := nr.AssignTeams(userLicenses, mapping); err != nil {
+			slog.Warn("could not attribute licenses to teams", "error", err)
+		} else {
+			teams := providers.SectionTable{
+				Title: fmt.Sprintf("License Cost by Team (%s assignment)", mapping.Assignment),
+				Columns: []providers.SectionColumn{
+					{Key: "team", Title: "Team", Type: providers.ColumnString},
+					{Key: "users", Title: "Users", Type: providers.ColumnNumber},
+					{Key: "inactive_users", Title: "Inactive", Type: providers.ColumnNumber},
+					{Key: "cost", Title: "Cost", Type: providers.ColumnCurrency, Unit: "USD"},
+					{Key: "inactive_cost", Title: "Inactive Cost", Type: providers.ColumnCurrency, Unit: "USD"},
+				},
+			}
+			for _, teamCost := range teamLicenseCosts(userLicenses) {
+				teams.AddRow(teamCost.Team, teamCost.Users, teamCost.InactiveUsers, teamCost.Cost, teamCost.InactiveCost)
+			}
+			section.Tables = append(section.Tables, teams)
+		}
+	}
 
 	// Sort users by inactive status first, then by license type
 	sort.Slice(userLicenses, func(i, j int) bool {
@@ -877,23 +817,28 @@ func (nr *NewRelicProvider) GetLicenseUsageReport(daysInactive int) (string, err
 		return userLicenses[i].LicenseType < userLicenses[j].LicenseType
 	})
 
+	// Detailed user breakdown
+	users := providers.SectionTable{
+		Title: "Detailed License Usage",
+		Columns: []providers.SectionColumn{
+			{Key: "user_name", Title: "Username", Type: providers.ColumnString},
+			{Key: "email", Title: "Email", Type: providers.ColumnString},
+			{Key: "license_type", Title: "License Type", Type: providers.ColumnString},
+			{Key: "last_active", Title: "Last Active", Type: providers.ColumnDateTime},
+			{Key: "status", Title: "Status", Type: providers.ColumnString},
+			{Key: "cost", Title: "Cost", Type: providers.ColumnCurrency, Unit: "USD"},
+		},
+	}
 	for _, user := range userLicenses {
 		status := "Active"
 		if !user.IsActive {
 			status = "Inactive"
 		}
-
-		// Truncate long fields for better formatting
-		userName := truncateString(user.UserName, 16)
-		email := truncateString(user.Email, 22)
-		licenseType := truncateString(user.LicenseType, 16)
-
-		report.WriteString(fmt.Sprintf("%-16s| %-22s| %-16s| %-19s | %-8s | $%.2f\n",
-			userName, email, licenseType, user.LastActive.Format("2006-01-02 15:04:05"),
-			status, user.Cost))
+		users.AddRow(user.UserName, user.Email, user.LicenseType, user.LastActive, status, user.Cost)
 	}
+	section.Tables = append(section.Tables, users)
 
-	return report.String(), nil
+	return section, nil
 }
 
 // GetDetailedLicenseData retrieves detailed information about each user license
@@ -978,14 +923,14 @@ func (nr *NewRelicProvider) GetDetailedLicenseData() ([]UserLicenseData, error)
 		// Check if we got a valid response with authentication domains
 		authDomains := responseData.Actor.Organization.UserManagement.AuthenticationDomains.AuthenticationDomains
 		if len(authDomains) == 0 {
-			fmt.Printf("Warning: No authentication domains found for domain ID %s\n", domainID)
+			slog.Warn("no authentication domains found", "domain", domainID)
 			continue
 		}
 
 		// Extract and convert users to our UserLicenseData type
 		for _, domain := range authDomains {
 			users := domain.Users.Users
-			fmt.Printf("Found %d users in domain %s\n", len(users), domainID)
+			slog.Debug("found users in domain", "count", len(users), "domain", domainID)
 
 			for _, user := range users {
 				// Parse last active time
@@ -1023,17 +968,9 @@ func (nr *NewRelicProvider) GetDetailedLicenseData() ([]UserLicenseData, error)
 
 	// If no real users were found, return empty slice instead of sample data
 	if len(allUserData) == 0 {
-		fmt.Println("No real user data found")
+		slog.Info("no user data found")
 		return []UserLicenseData{}, nil
 	}
 
 	return allUserData, nil
 }
-
-// Helper function to truncate strings for better report formatting
-func truncateString(s string, maxLength int) string {
-	if len(s) <= maxLength {
-		return s
-	}
-	return s[:maxLength-3] + "..."
-}
@@ -0,0 +1,94 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nrqlQuery is the NerdGraph query used to run a single NRQL statement against an account
+const nrqlQuery = `query($accountId: Int!, $nrql: Nrql!) {
+	actor {
+		account(id: $accountId) {
+			nrql(query: $nrql) {
+				results
+			}
+		}
+	}
+}`
+
+// runNRQL executes an NRQL query against the given account and returns the raw result rows
+func (nr *NewRelicProvider) runNRQL(accountID, nrql string) ([]map[string]interface{}, error) {
+	id, err := strconv.Atoi(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid New Relic account ID %q: %w", accountID, err)
+	}
+
+	resp, err := nr.client.NerdGraph.Query(nrqlQuery, map[string]interface{}{
+		"accountId": id,
+		"nrql":      nrql,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error running NRQL for account %s: %w", accountID, err)
+	}
+
+	var response struct {
+		Actor struct {
+			Account struct {
+				NRQL struct {
+					Results []map[string]interface{} `json:"results"`
+				} `json:"nrql"`
+			} `json:"account"`
+		} `json:"actor"`
+	}
+
+	jsonData, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling NRQL response: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshalling NRQL response: %w", err)
+	}
+
+	return response.Actor.Account.NRQL.Results, nil
+}
+
+// nrqlIdentifier quotes an event type or attribute name for use in NRQL
+func nrqlIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "") + "`"
+}
+
+// nrqlString quotes a literal string value for use in NRQL
+func nrqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "\\'") + "'"
+}
+
+// resultFloat extracts a numeric value from an NRQL result row
+func resultFloat(result map[string]interface{}, key string) (float64, bool) {
+	switch v := result[key].(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// resultString extracts a string value from an NRQL result row
+func resultString(result map[string]interface{}, key string) (string, bool) {
+	switch v := result[key].(type) {
+	case string:
+		return v, true
+	case []interface{}:
+		// Multi-attribute facets come back as a list of values
+		parts := make([]string, 0, len(v))
+		for _, part := range v {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		return strings.Join(parts, ", "), true
+	}
+	return "", false
+}
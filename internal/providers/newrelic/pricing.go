@@ -0,0 +1,69 @@
+package newrelic
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/spf13/viper"
+)
+
+// Data options offered by New Relic's consumption pricing
+const (
+	DataOptionOriginal = "original"
+	DataOptionDataPlus = "data_plus"
+)
+
+// dataOption returns the configured data option (newrelic.data_option), defaulting to original
+func dataOption() string {
+	option := strings.ToLower(viper.GetString("newrelic.data_option"))
+	if option != DataOptionOriginal && option != DataOptionDataPlus {
+		return DataOptionOriginal
+	}
+	return option
+}
+
+// pricingCatalog returns the configured pricing catalog, or the built-in one when the
+// configured catalog cannot be read. It is resolved once per provider, so a bad
+// catalog is only warned about once.
+func (nr *NewRelicProvider) pricingCatalog() *pricing.Catalog {
+	if nr.catalog != nil {
+		return nr.catalog
+	}
+
+	catalog, err := pricing.FromConfig(viper.GetViper())
+	if err != nil {
+		slog.Warn("using the built-in pricing catalog", "error", err)
+		catalog = pricing.Default()
+	}
+	nr.catalog = catalog
+	return catalog
+}
+
+// dataPricePerGB returns the catalog price per GB ingested for the data option,
+// honoring newrelic.pricing.data_per_gb overrides
+func (nr *NewRelicProvider) dataPricePerGB() float64 {
+	if price := viper.GetFloat64("newrelic.pricing.data_per_gb"); price > 0 {
+		return price
+	}
+	p, _ := nr.pricingCatalog().Lookup("newrelic", "", dataOption(), pricing.AnyService, "DataSize")
+	return p.Price
+}
+
+// licensePrices returns the catalog's monthly list price per license type; the billing
+// API does not expose negotiated prices
+func (nr *NewRelicProvider) licensePrices() map[string]float64 {
+	prices := make(map[string]float64)
+	for _, p := range nr.pricingCatalog().Provider("newrelic") {
+		if p.Service == "Licenses" {
+			prices[strings.TrimSuffix(p.Metric, " Licenses")] = p.Price
+		}
+	}
+	return prices
+}
+
+// licensePrice returns the monthly price of a license type, or 0 when it is unknown
+func (nr *NewRelicProvider) licensePrice(licenseType string) float64 {
+	p, _ := nr.pricingCatalog().Lookup("newrelic", "", "", "Licenses", licenseType+" Licenses")
+	return p.Price
+}
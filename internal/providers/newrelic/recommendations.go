@@ -0,0 +1,90 @@
+package newrelic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/recommend"
+	"github.com/spf13/viper"
+)
+
+// RuleInactiveLicenses recommends reclaiming the licenses of users who have not been
+// active for newrelic.inactive_days
+const RuleInactiveLicenses = "newrelic-inactive-licenses"
+
+// neverActive is the last active time GetDetailedLicenseData assigns to users without
+// a recorded activity
+var neverActive = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// inactiveDays returns newrelic.inactive_days, defaulting to 30
+func inactiveDays() int {
+	if days := viper.GetInt("newrelic.inactive_days"); days > 0 {
+		return days
+	}
+	return 30
+}
+
+// markInactiveLicenses prices each license and marks the users who have not been
+// active for more than daysInactive days before now as inactive
+func (nr *NewRelicProvider) markInactiveLicenses(userLicenses []UserLicenseData, daysInactive int, now time.Time) {
+	threshold := now.AddDate(0, 0, -daysInactive)
+	for i := range userLicenses {
+		userLicenses[i].Cost = nr.licensePrice(userLicenses[i].LicenseType)
+		userLicenses[i].IsActive = !userLicenses[i].LastActive.Before(threshold)
+	}
+}
+
+// inactiveLicenseRecommendations recommends reclaiming each paid license of an
+// inactive user. Users idle for more than twice the threshold, or never active, are
+// high confidence.
+func (nr *NewRelicProvider) inactiveLicenseRecommendations() ([]recommend.Recommendation, error) {
+	userLicenses, err := nr.GetDetailedLicenseData()
+	if err != nil {
+		return nil, fmt.Errorf("error getting detailed license data: %w", err)
+	}
+
+	days := inactiveDays()
+	now := time.Now()
+	nr.markInactiveLicenses(userLicenses, days, now)
+
+	var recs []recommend.Recommendation
+	for _, user := range userLicenses {
+		if user.IsActive || user.Cost == 0 {
+			continue
+		}
+
+		resource := user.Email
+		if resource == "" {
+			resource = user.UserName
+		}
+		id := user.UserID
+		if id == "" {
+			id = resource
+		}
+
+		confidence := recommend.ConfidenceHigh
+		activity := "no recorded activity"
+		if !user.LastActive.Equal(neverActive) {
+			idle := int(now.Sub(user.LastActive).Hours() / 24)
+			activity = fmt.Sprintf("last active %s (%d days ago, threshold %d days)", user.LastActive.Format("2006-01-02"), idle, days)
+			if idle <= 2*days {
+				confidence = recommend.ConfidenceMedium
+			}
+		}
+
+		recs = append(recs, recommend.Recommendation{
+			ID:             RuleInactiveLicenses + "/" + id,
+			Resource:       resource,
+			Action:         fmt.Sprintf("Downgrade or remove the %s license", user.LicenseType),
+			MonthlySavings: user.Cost,
+			Currency:       "USD",
+			Confidence:     confidence,
+			Evidence: []string{
+				activity,
+				fmt.Sprintf("%s license at %.2f USD/month", user.LicenseType, user.Cost),
+			},
+		})
+	}
+
+	return recs, nil
+}
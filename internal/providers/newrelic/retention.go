@@ -0,0 +1,354 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// defaultRetentionPricePerGBMonth is the list price for each GB kept for an extra 30 days
+const defaultRetentionPricePerGBMonth = 0.05
+
+// Retention included in the price of each data option before extended retention is billed
+var defaultIncludedRetentionDays = map[string]int{
+	DataOptionOriginal: 30,
+	DataOptionDataPlus: 90,
+}
+
+// namespaceUsageMetrics maps retention namespaces to the NrConsumption usage metric
+// that records how much data was ingested into them
+var namespaceUsageMetrics = map[string]string{
+	"APM":            "ApmEventsBytes",
+	"Browser":        "BrowserEventsBytes",
+	"Custom events":  "CustomEventsBytes",
+	"Infrastructure": "InfraHostBytes",
+	"Logging":        "LoggingBytes",
+	"Metrics":        "MetricsBytes",
+	"Mobile":         "MobileEventsBytes",
+	"Serverless":     "ServerlessBytes",
+	"Synthetics":     "SyntheticsBytes",
+	"Tracing":        "TracingBytes",
+}
+
+// namespaceIngest returns the GB ingested into a retention namespace, matching
+// namespaces and usage metrics case-insensitively. ok is false when the namespace has no
+// usage metric or the ingest could not be read, so its volume is unknown rather than 0.
+func namespaceIngest(ingest map[string]float64, namespace string) (float64, bool) {
+	if ingest == nil {
+		return 0, false
+	}
+	for ns, metric := range namespaceUsageMetrics {
+		if !strings.EqualFold(ns, namespace) {
+			continue
+		}
+		for m, gb := range ingest {
+			if strings.EqualFold(m, metric) {
+				return gb, true
+			}
+		}
+		return 0, true // Nothing ingested under the metric
+	}
+	return 0, false
+}
+
+// RetentionPolicy is the maximum retention we allow per namespace
+type RetentionPolicy struct {
+	DefaultDays     int
+	NamespaceDays   map[string]int
+	IncludedDays    int
+	PricePerGBMonth float64
+}
+
+// RetentionPolicyFromConfig reads newrelic.retention from the configuration. It returns
+// ok=false when no policy is configured.
+//
+//	newrelic:
+//	  retention:
+//	    policy:
+//	      default: 30
+//	      namespaces:
+//	        Logging: 14
+//	    included_days: 90          # defaults by newrelic.data_option
+func RetentionPolicyFromConfig(config *viper.Viper) (RetentionPolicy, bool) {
+	if !config.IsSet("newrelic.retention.policy") {
+		return RetentionPolicy{}, false
+	}
+
+	policy := RetentionPolicy{
+		DefaultDays:   config.GetInt("newrelic.retention.policy.default"),
+		NamespaceDays: make(map[string]int),
+	}
+	policy.IncludedDays, policy.PricePerGBMonth = retentionPricing(config)
+
+	for namespace, days := range config.GetStringMap("newrelic.retention.policy.namespaces") {
+		if parsed, err := strconv.Atoi(fmt.Sprint(days)); err == nil {
+			policy.NamespaceDays[strings.ToLower(namespace)] = parsed
+		}
+	}
+
+	return policy, true
+}
+
+// retentionPricing returns the retention included in the data option and the price of
+// each GB kept for an extra 30 days, honoring newrelic.retention.included_days and
+// newrelic.pricing.retention_per_gb_month
+func retentionPricing(config *viper.Viper) (int, float64) {
+	includedDays := config.GetInt("newrelic.retention.included_days")
+	if includedDays == 0 {
+		includedDays = defaultIncludedRetentionDays[dataOption()]
+	}
+	price := config.GetFloat64("newrelic.pricing.retention_per_gb_month")
+	if price == 0 {
+		price = defaultRetentionPricePerGBMonth
+	}
+	return includedDays, price
+}
+
+// MaxDays returns the policy's retention limit for a namespace, or 0 if unrestricted
+func (p RetentionPolicy) MaxDays(namespace string) int {
+	// Viper lowercases map keys, so namespaces are matched case-insensitively
+	if days, ok := p.NamespaceDays[strings.ToLower(namespace)]; ok {
+		return days
+	}
+	return p.DefaultDays
+}
+
+// extendedCost is the monthly cost of keeping monthlyGB for retentionDays
+func (p RetentionPolicy) extendedCost(monthlyGB float64, retentionDays int) float64 {
+	extraDays := math.Max(0, float64(retentionDays-p.IncludedDays))
+	return monthlyGB * extraDays / 30.0 * p.PricePerGBMonth
+}
+
+// RetentionSetting is an account's configured retention for one namespace
+type RetentionSetting struct {
+	AccountID        string  `json:"accountId"`
+	AccountName      string  `json:"accountName"`
+	Namespace        string  `json:"namespace"`
+	RetentionDays    int     `json:"retentionDays"`
+	PolicyDays       int     `json:"policyDays"`
+	MonthlyGB        float64 `json:"monthlyGb"`
+	ExtendedCost     float64 `json:"extendedRetentionCost"`
+	ProjectedSavings float64 `json:"projectedSavings"`
+	ExceedsPolicy    bool    `json:"exceedsPolicy"`
+	IngestUnknown    bool    `json:"ingestUnknown,omitempty"` // No usage metric for the namespace; cost and savings are not estimated
+}
+
+// AuditRetention compares every allowed account's retention settings to the policy and
+// estimates the monthly cost of extended retention and the savings of complying
+func (nr *NewRelicProvider) AuditRetention(policy RetentionPolicy) ([]RetentionSetting, error) {
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []RetentionSetting
+	for _, account := range accounts {
+		retentions, err := nr.getRetentionSettings(account.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ingest, err := nr.getMonthlyIngestByUsageMetric(account.ID)
+		if err != nil {
+			slog.Warn("could not get ingest volume", "account", account.ID, "error", err)
+		}
+
+		for namespace, days := range retentions {
+			monthlyGB, known := namespaceIngest(ingest, namespace)
+			if !known {
+				slog.Warn("no ingest volume for retention namespace", "account", account.ID, "namespace", namespace)
+			}
+			policyDays := policy.MaxDays(namespace)
+
+			setting := RetentionSetting{
+				AccountID:     account.ID,
+				AccountName:   account.Name,
+				Namespace:     namespace,
+				RetentionDays: days,
+				PolicyDays:    policyDays,
+				MonthlyGB:     monthlyGB,
+				ExtendedCost:  policy.extendedCost(monthlyGB, days),
+				IngestUnknown: !known,
+			}
+
+			if policyDays > 0 && days > policyDays {
+				setting.ExceedsPolicy = true
+				setting.ProjectedSavings = setting.ExtendedCost - policy.extendedCost(monthlyGB, policyDays)
+			}
+
+			settings = append(settings, setting)
+		}
+	}
+
+	sort.Slice(settings, func(i, j int) bool {
+		if settings[i].ProjectedSavings != settings[j].ProjectedSavings {
+			return settings[i].ProjectedSavings > settings[j].ProjectedSavings
+		}
+		if settings[i].AccountID != settings[j].AccountID {
+			return settings[i].AccountID < settings[j].AccountID
+		}
+		return settings[i].Namespace < settings[j].Namespace
+	})
+
+	return settings, nil
+}
+
+// getRetentionSettings reads the retention in days per namespace for one account
+func (nr *NewRelicProvider) getRetentionSettings(accountID string) (map[string]int, error) {
+	id, err := strconv.Atoi(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid New Relic account ID %q: %w", accountID, err)
+	}
+
+	query := `query($accountId: Int!) {
+		actor {
+			account(id: $accountId) {
+				dataManagement {
+					eventRetentionPolicies {
+						namespace
+						retentionInDays
+					}
+				}
+			}
+		}
+	}`
+
+	resp, err := nr.client.NerdGraph.Query(query, map[string]interface{}{"accountId": id})
+	if err != nil {
+		return nil, fmt.Errorf("error querying retention settings for account %s: %w", accountID, err)
+	}
+
+	var response struct {
+		Actor struct {
+			Account struct {
+				DataManagement struct {
+					EventRetentionPolicies []struct {
+						Namespace       string `json:"namespace"`
+						RetentionInDays int    `json:"retentionInDays"`
+					} `json:"eventRetentionPolicies"`
+				} `json:"dataManagement"`
+			} `json:"account"`
+		} `json:"actor"`
+	}
+
+	jsonData, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling retention response: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, &response); err != nil {
+		return nil, fmt.Errorf("error unmarshalling retention response: %w", err)
+	}
+
+	retentions := make(map[string]int)
+	for _, policy := range response.Actor.Account.DataManagement.EventRetentionPolicies {
+		retentions[policy.Namespace] = policy.RetentionInDays
+	}
+
+	return retentions, nil
+}
+
+// getMonthlyIngestByUsageMetric returns GB ingested over the last 30 days per usage metric
+func (nr *NewRelicProvider) getMonthlyIngestByUsageMetric(accountID string) (map[string]float64, error) {
+	results, err := nr.runNRQL(accountID, "SELECT sum(GigabytesIngested) AS gb FROM NrConsumption WHERE productLine = 'DataPlatform' SINCE 30 days ago FACET usageMetric LIMIT MAX")
+	if err != nil {
+		return nil, err
+	}
+
+	ingest := make(map[string]float64)
+	for _, result := range results {
+		metric, ok := resultString(result, "usageMetric")
+		if !ok {
+			continue
+		}
+		if gb, ok := resultFloat(result, "gb"); ok {
+			ingest[metric] = gb
+		}
+	}
+
+	return ingest, nil
+}
+
+// GetRetentionAuditReport builds the retention audit section, listing the namespaces that
+// exceed the policy first
+func (nr *NewRelicProvider) GetRetentionAuditReport(policy RetentionPolicy) (providers.Section, error) {
+	section := providers.Section{ID: SectionRetention, Title: "Data Retention Audit", Order: 300}
+
+	settings, err := nr.AuditRetention(policy)
+	if err != nil {
+		return section, err
+	}
+
+	if len(settings) == 0 {
+		return section, nil
+	}
+
+	var totalExtended, totalSavings float64
+	violations := 0
+	for _, setting := range settings {
+		totalExtended += setting.ExtendedCost
+		totalSavings += setting.ProjectedSavings
+		if setting.ExceedsPolicy {
+			violations++
+		}
+	}
+
+	section.AddSummary("Included Retention (days)", policy.IncludedDays, providers.ColumnInteger, "days")
+	section.AddSummary("Extended Retention Price (per GB per 30 days)", policy.PricePerGBMonth, providers.ColumnCurrency, "USD")
+	section.AddSummary("Namespaces Audited", len(settings), providers.ColumnInteger, "")
+	section.AddSummary("Namespaces Exceeding Policy", violations, providers.ColumnInteger, "")
+	section.AddSummary("Extended Retention Cost (monthly)", totalExtended, providers.ColumnCurrency, "USD")
+	section.AddSummary("Projected Monthly Savings", totalSavings, providers.ColumnCurrency, "USD")
+
+	table := providers.SectionTable{
+		Title: "Retention by Namespace",
+		Columns: []providers.SectionColumn{
+			{Key: "account_id", Title: "Account", Type: providers.ColumnString},
+			{Key: "namespace", Title: "Namespace", Type: providers.ColumnString},
+			{Key: "retention_days", Title: "Retention (days)", Type: providers.ColumnInteger, Unit: "days"},
+			{Key: "policy_days", Title: "Policy (days)", Type: providers.ColumnString},
+			{Key: "gb_per_month", Title: "GB/Month", Type: providers.ColumnNumber, Unit: "GB"},
+			{Key: "extended_cost", Title: "Extended Cost", Type: providers.ColumnCurrency, Unit: "USD"},
+			{Key: "savings", Title: "Savings", Type: providers.ColumnCurrency, Unit: "USD"},
+			{Key: "status", Title: "Status", Type: providers.ColumnString},
+		},
+	}
+
+	for _, setting := range settings {
+		status := "OK"
+		if setting.ExceedsPolicy {
+			status = "EXCEEDS"
+			detail := "Lower the namespace retention in Data management to the policy maximum"
+			if setting.IngestUnknown {
+				detail += "; savings are not estimated as the namespace's ingest volume is unknown"
+			}
+			section.Findings = append(section.Findings, providers.Finding{
+				Severity: providers.SeverityWarning,
+				Message:  fmt.Sprintf("%s in account %s keeps data for %d days, the policy allows %d", setting.Namespace, setting.AccountID, setting.RetentionDays, setting.PolicyDays),
+				Detail:   detail,
+				Impact:   setting.ProjectedSavings,
+				Currency: "USD",
+			})
+		}
+		if setting.IngestUnknown {
+			status += " (GB unknown)"
+		}
+		policyDays := "-"
+		if setting.PolicyDays > 0 {
+			policyDays = strconv.Itoa(setting.PolicyDays)
+		}
+
+		table.AddRow(setting.AccountID, setting.Namespace, setting.RetentionDays, policyDays,
+			setting.MonthlyGB, setting.ExtendedCost, setting.ProjectedSavings, status)
+	}
+	section.Tables = append(section.Tables, table)
+
+	return section, nil
+}
@@ -0,0 +1,84 @@
+package newrelic
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ilhicas/observability-cost-center/internal/simulate"
+	"github.com/spf13/viper"
+)
+
+// SimulationBaseline describes the last 30 days of ingest per account and event type,
+// the retention and ingest of each namespace, and the users of each license type
+func (nr *NewRelicProvider) SimulationBaseline() (*simulate.Baseline, error) {
+	accounts, err := nr.listAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	includedDays, retentionPrice := retentionPricing(viper.GetViper())
+	baseline := &simulate.Baseline{
+		Provider: "newrelic",
+		Pricing: simulate.Pricing{
+			Currency:              "USD",
+			IngestPerGB:           nr.dataPricePerGB(),
+			IncludedRetentionDays: includedDays,
+			RetentionPerGBMonth:   retentionPrice,
+			Licenses:              nr.licensePrices(),
+		},
+	}
+
+	for _, account := range accounts {
+		volumes, err := nr.eventTypeVolumes(account.ID, "SINCE 30 days ago")
+		if err != nil {
+			return nil, fmt.Errorf("error estimating event type volumes for account %s: %w", account.ID, err)
+		}
+		for eventType, gb := range volumes {
+			baseline.Resources = append(baseline.Resources, simulate.Resource{
+				Kind:      simulate.KindEventType,
+				AccountID: account.ID,
+				Name:      eventType,
+				MonthlyGB: gb,
+			})
+		}
+
+		retentions, err := nr.getRetentionSettings(account.ID)
+		if err != nil {
+			return nil, err
+		}
+		ingest, err := nr.getMonthlyIngestByUsageMetric(account.ID)
+		if err != nil {
+			slog.Warn("could not get ingest volume", "account", account.ID, "error", err)
+		}
+		for namespace, days := range retentions {
+			monthlyGB, known := namespaceIngest(ingest, namespace)
+			if !known {
+				slog.Warn("no ingest volume for retention namespace; it is left out of retention scenarios", "account", account.ID, "namespace", namespace)
+				continue
+			}
+			baseline.Resources = append(baseline.Resources, simulate.Resource{
+				Kind:          simulate.KindNamespace,
+				AccountID:     account.ID,
+				Name:          namespace,
+				MonthlyGB:     monthlyGB,
+				RetentionDays: days,
+			})
+		}
+	}
+
+	// Licenses belong to the organization rather than to an account
+	licenses, err := nr.GetLicenseInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get license info: %w", err)
+	}
+	for _, license := range licenses {
+		baseline.Resources = append(baseline.Resources, simulate.Resource{
+			Kind:  simulate.KindLicense,
+			Name:  license.Type,
+			Count: float64(license.UsedLicenses),
+		})
+	}
+
+	slog.Debug("fetched simulation baseline", "provider", "newrelic", "resources", len(baseline.Resources))
+	return baseline, nil
+}
@@ -0,0 +1,234 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Team assignment modes for users that belong to groups mapped to several teams
+const (
+	TeamAssignmentSplit    = "split"
+	TeamAssignmentPriority = "priority"
+)
+
+// UnassignedTeam receives the license cost of users in no mapped group
+const UnassignedTeam = "Unassigned"
+
+// TeamMapping maps NerdGraph user groups to teams
+type TeamMapping struct {
+	Groups     map[string]string // Lowercased group display name -> team
+	Priority   []string          // Teams in order of precedence for priority assignment
+	Assignment string            // split or priority
+}
+
+// TeamMappingFromConfig reads newrelic.teams from the configuration. It returns
+// ok=false when no group mapping is configured.
+//
+//	newrelic:
+//	  teams:
+//	    mapping:
+//	      Payments Engineers: payments
+//	      Platform Admins: platform
+//	    assignment: priority        # or split (default)
+//	    priority: [platform, payments]
+func TeamMappingFromConfig(config *viper.Viper) (TeamMapping, bool) {
+	groups := config.GetStringMapString("newrelic.teams.mapping")
+	if len(groups) == 0 {
+		return TeamMapping{}, false
+	}
+
+	mapping := TeamMapping{
+		Groups:     make(map[string]string, len(groups)),
+		Priority:   config.GetStringSlice("newrelic.teams.priority"),
+		Assignment: strings.ToLower(config.GetString("newrelic.teams.assignment")),
+	}
+	for group, team := range groups {
+		mapping.Groups[strings.ToLower(group)] = team
+	}
+	if mapping.Assignment != TeamAssignmentPriority {
+		mapping.Assignment = TeamAssignmentSplit
+	}
+
+	return mapping, true
+}
+
+// teamsFor returns the share of a user's license owned by each team
+func (m TeamMapping) teamsFor(groups []string) map[string]float64 {
+	teamSet := make(map[string]bool)
+	for _, group := range groups {
+		if team, ok := m.Groups[strings.ToLower(group)]; ok {
+			teamSet[team] = true
+		}
+	}
+
+	if len(teamSet) == 0 {
+		return map[string]float64{UnassignedTeam: 1}
+	}
+
+	// Priority assignment gives the whole license to the highest ranked team
+	if m.Assignment == TeamAssignmentPriority {
+		for _, team := range m.Priority {
+			if teamSet[team] {
+				return map[string]float64{team: 1}
+			}
+		}
+		// Fall back to a stable choice when none of the teams is ranked
+		teams := make([]string, 0, len(teamSet))
+		for team := range teamSet {
+			teams = append(teams, team)
+		}
+		sort.Strings(teams)
+		return map[string]float64{teams[0]: 1}
+	}
+
+	shares := make(map[string]float64, len(teamSet))
+	for team := range teamSet {
+		shares[team] = 1 / float64(len(teamSet))
+	}
+	return shares
+}
+
+// TeamLicenseCost is the license cost attributed to one team
+type TeamLicenseCost struct {
+	Team          string  `json:"team"`
+	Users         float64 `json:"users"` // Fractional when users are split across teams
+	InactiveUsers float64 `json:"inactiveUsers"`
+	Cost          float64 `json:"cost"`
+	InactiveCost  float64 `json:"inactiveCost"`
+}
+
+// getGroupMemberships returns the display names of the groups each user belongs to, keyed by user ID
+func (nr *NewRelicProvider) getGroupMemberships() (map[string][]string, error) {
+	domainIDs, err := nr.getAuthDomainIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authentication domain IDs: %w", err)
+	}
+
+	memberships := make(map[string][]string)
+	for _, domainID := range domainIDs {
+		query := fmt.Sprintf(`{
+			actor {
+				organization {
+					userManagement {
+						authenticationDomains(id: "%s") {
+							authenticationDomains {
+								groups {
+									groups {
+										id
+										displayName
+										users {
+											users {
+												id
+											}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}`, domainID)
+
+		resp, err := nr.client.NerdGraph.Query(query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error querying groups for domain %s: %w", domainID, err)
+		}
+
+		var responseData struct {
+			Actor struct {
+				Organization struct {
+					UserManagement struct {
+						AuthenticationDomains struct {
+							AuthenticationDomains []struct {
+								Groups struct {
+									Groups []struct {
+										ID          string `json:"id"`
+										DisplayName string `json:"displayName"`
+										Users       struct {
+											Users []struct {
+												ID string `json:"id"`
+											} `json:"users"`
+										} `json:"users"`
+									} `json:"groups"`
+								} `json:"groups"`
+							} `json:"authenticationDomains"`
+						} `json:"authenticationDomains"`
+					} `json:"userManagement"`
+				} `json:"organization"`
+			} `json:"actor"`
+		}
+
+		jsonData, err := json.Marshal(resp)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling groups response for domain %s: %w", domainID, err)
+		}
+
+		if err := json.Unmarshal(jsonData, &responseData); err != nil {
+			return nil, fmt.Errorf("error unmarshalling groups response for domain %s: %w", domainID, err)
+		}
+
+		for _, domain := range responseData.Actor.Organization.UserManagement.AuthenticationDomains.AuthenticationDomains {
+			for _, group := range domain.Groups.Groups {
+				for _, user := range group.Users.Users {
+					memberships[user.ID] = append(memberships[user.ID], group.DisplayName)
+				}
+			}
+		}
+	}
+
+	return memberships, nil
+}
+
+// AssignTeams fills in the groups and team shares of each user license
+func (nr *NewRelicProvider) AssignTeams(userLicenses []UserLicenseData, mapping TeamMapping) error {
+	memberships, err := nr.getGroupMemberships()
+	if err != nil {
+		return err
+	}
+
+	for i := range userLicenses {
+		userLicenses[i].Groups = memberships[userLicenses[i].UserID]
+		userLicenses[i].Teams = mapping.teamsFor(userLicenses[i].Groups)
+	}
+
+	return nil
+}
+
+// teamLicenseCosts aggregates priced user licenses per team, ordered by cost
+func teamLicenseCosts(userLicenses []UserLicenseData) []TeamLicenseCost {
+	byTeam := make(map[string]*TeamLicenseCost)
+	for _, user := range userLicenses {
+		for team, share := range user.Teams {
+			teamCost, ok := byTeam[team]
+			if !ok {
+				teamCost = &TeamLicenseCost{Team: team}
+				byTeam[team] = teamCost
+			}
+			teamCost.Users += share
+			teamCost.Cost += user.Cost * share
+			if !user.IsActive {
+				teamCost.InactiveUsers += share
+				teamCost.InactiveCost += user.Cost * share
+			}
+		}
+	}
+
+	costs := make([]TeamLicenseCost, 0, len(byTeam))
+	for _, teamCost := range byTeam {
+		costs = append(costs, *teamCost)
+	}
+
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].Cost != costs[j].Cost {
+			return costs[i].Cost > costs[j].Cost
+		}
+		return costs[i].Team < costs[j].Team
+	})
+
+	return costs
+}
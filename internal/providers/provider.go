@@ -28,4 +28,5 @@ type CostData struct {
 	Quantity    float64   `json:"quantity,omitempty"`
 	UsageUnit   string    `json:"usageUnit,omitempty"`
 	Description string    `json:"description,omitempty"`
+	Estimated   bool      `json:"estimated,omitempty"` // Priced from usage with the pricing catalog, not billed
 }
@@ -0,0 +1,81 @@
+package providers
+
+// ColumnType tells renderers how to format and align a value
+type ColumnType string
+
+const (
+	ColumnString   ColumnType = "string"
+	ColumnInteger  ColumnType = "integer"  // int or float64 without decimals
+	ColumnNumber   ColumnType = "number"   // float64, Unit names what is counted
+	ColumnCurrency ColumnType = "currency" // float64, Unit holds the currency code
+	ColumnPercent  ColumnType = "percent"  // float64 ratio, 0.25 is 25%
+	ColumnDate     ColumnType = "date"     // time.Time
+	ColumnDateTime ColumnType = "datetime" // time.Time
+)
+
+// Finding severities, from least to most urgent
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Section is a structured report section contributed by a provider, such as the
+// New Relic license audit. Sections are rendered in Order, then by Title.
+type Section struct {
+	ID       string         `json:"id"` // Stable identifier, e.g. newrelic.licenses
+	Title    string         `json:"title"`
+	Order    int            `json:"order"`
+	Summary  []SectionField `json:"summary,omitempty"`
+	Tables   []SectionTable `json:"tables,omitempty"`
+	Findings []Finding      `json:"findings,omitempty"`
+	Notes    []string       `json:"notes,omitempty"`
+}
+
+// SectionField is a typed key-value pair of a section summary
+type SectionField struct {
+	Label string      `json:"label"`
+	Value interface{} `json:"value"`
+	Type  ColumnType  `json:"type"`
+	Unit  string      `json:"unit,omitempty"`
+}
+
+// SectionColumn describes one column of a section table
+type SectionColumn struct {
+	Key   string     `json:"key"`
+	Title string     `json:"title"`
+	Type  ColumnType `json:"type"`
+	Unit  string     `json:"unit,omitempty"`
+}
+
+// SectionTable is a table whose cells hold typed values (string, int, float64 or
+// time.Time) matching the column types
+type SectionTable struct {
+	Title   string          `json:"title"`
+	Columns []SectionColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// Finding is something a section wants the reader to act on
+type Finding struct {
+	Severity string  `json:"severity"`
+	Message  string  `json:"message"`
+	Detail   string  `json:"detail,omitempty"`
+	Impact   float64 `json:"impact,omitempty"` // Estimated monthly cost at stake
+	Currency string  `json:"currency,omitempty"`
+}
+
+// AddSummary appends a typed summary field
+func (s *Section) AddSummary(label string, value interface{}, typ ColumnType, unit string) {
+	s.Summary = append(s.Summary, SectionField{Label: label, Value: value, Type: typ, Unit: unit})
+}
+
+// AddRow appends a row to the table
+func (t *SectionTable) AddRow(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+// IsEmpty reports whether the section has nothing to show
+func (s Section) IsEmpty() bool {
+	return len(s.Summary) == 0 && len(s.Tables) == 0 && len(s.Findings) == 0 && len(s.Notes) == 0
+}
@@ -0,0 +1,75 @@
+// Package recommend collects savings recommendations, such as reclaiming inactive
+// licenses, from rules that providers register for their own data.
+package recommend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Confidence levels, from least to most certain that acting saves the estimate
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+var confidenceRank = map[string]int{ConfidenceLow: 1, ConfidenceMedium: 2, ConfidenceHigh: 3}
+
+// Recommendation is a change to one resource that is expected to save money
+type Recommendation struct {
+	// ID identifies the recommendation across runs, e.g. "newrelic-inactive-licenses/<user>".
+	// Recommendations with the same ID are duplicates.
+	ID             string   `json:"id"`
+	Rule           string   `json:"rule"`
+	Provider       string   `json:"provider"`
+	AccountID      string   `json:"accountId,omitempty"`
+	Resource       string   `json:"resource"`
+	Action         string   `json:"action"`
+	MonthlySavings float64  `json:"monthlySavings"`
+	Currency       string   `json:"currency"`
+	Confidence     string   `json:"confidence"`
+	Evidence       []string `json:"evidence,omitempty"`
+}
+
+// Rule finds recommendations in the data of one provider
+type Rule struct {
+	ID          string
+	Provider    string
+	Description string
+	Evaluate    func(provider providers.Provider) ([]Recommendation, error)
+}
+
+// rules holds the registered rules in registration order
+var rules []Rule
+
+// RegisterRule registers a rule; providers register theirs from init
+func RegisterRule(rule Rule) {
+	for _, existing := range rules {
+		if existing.ID == rule.ID {
+			panic(fmt.Sprintf("recommendation rule registered twice: %s", rule.ID))
+		}
+	}
+	rule.Provider = strings.ToLower(rule.Provider)
+	rules = append(rules, rule)
+}
+
+// Rules returns the rules registered for a provider in registration order, or every
+// rule when provider is empty
+func Rules(provider string) []Rule {
+	var matching []Rule
+	for _, rule := range rules {
+		if provider == "" || strings.EqualFold(rule.Provider, provider) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching
+}
+
+// ValidConfidence reports whether level is a known confidence level
+func ValidConfidence(level string) bool {
+	_, ok := confidenceRank[level]
+	return ok
+}
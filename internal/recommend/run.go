@@ -0,0 +1,136 @@
+package recommend
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Run evaluates the rules registered for the named provider ("aws", "newrelic"). A
+// rule that fails is logged and skipped so the others still produce recommendations;
+// the number of failed rules is returned.
+func Run(name string, provider providers.Provider, rules []Rule) ([]Recommendation, int) {
+	var recs []Recommendation
+	failed := 0
+	for _, rule := range rules {
+		if !strings.EqualFold(rule.Provider, name) {
+			continue
+		}
+		slog.Info("evaluating recommendation rule", "rule", rule.ID, "provider", rule.Provider)
+		found, err := rule.Evaluate(provider)
+		if err != nil {
+			slog.Warn("error evaluating recommendation rule", "rule", rule.ID, "error", err)
+			failed++
+			continue
+		}
+		for i := range found {
+			found[i].Rule = rule.ID
+			found[i].Provider = rule.Provider
+		}
+		recs = append(recs, found...)
+	}
+	return recs, failed
+}
+
+// Filter selects the rules to run and the recommendations to keep; zero fields select
+// everything
+type Filter struct {
+	Rules         []string // Rule IDs
+	MinSavings    float64
+	MinConfidence string
+}
+
+// SelectRules returns the rules the filter selects, in the order of its Rules. A rule
+// ID that is not among rules is an error.
+func (f Filter) SelectRules(rules []Rule) ([]Rule, error) {
+	if len(f.Rules) == 0 {
+		return rules, nil
+	}
+	selected := make([]Rule, 0, len(f.Rules))
+	for _, id := range f.Rules {
+		found := false
+		for _, rule := range rules {
+			if rule.ID == id {
+				selected = append(selected, rule)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no recommendation rule %q", id)
+		}
+	}
+	return selected, nil
+}
+
+// Apply returns the recommendations the filter's savings and confidence thresholds select
+func (f Filter) Apply(recs []Recommendation) []Recommendation {
+	selected := make([]Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		if rec.MonthlySavings < f.MinSavings {
+			continue
+		}
+		if f.MinConfidence != "" && confidenceRank[rec.Confidence] < confidenceRank[f.MinConfidence] {
+			continue
+		}
+		selected = append(selected, rec)
+	}
+	return selected
+}
+
+// Dedupe merges recommendations with the same ID, such as a user listed in several
+// authentication domains. The one with the largest savings is kept, with the evidence
+// of the others added to it.
+func Dedupe(recs []Recommendation) []Recommendation {
+	index := make(map[string]int, len(recs))
+	deduped := make([]Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		i, seen := index[rec.ID]
+		if !seen {
+			index[rec.ID] = len(deduped)
+			deduped = append(deduped, rec)
+			continue
+		}
+
+		kept := deduped[i]
+		if rec.MonthlySavings > kept.MonthlySavings {
+			kept, rec = rec, kept
+		}
+		kept.Evidence = mergeEvidence(kept.Evidence, rec.Evidence)
+		deduped[i] = kept
+	}
+	return deduped
+}
+
+func mergeEvidence(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, e := range append(append([]string{}, a...), b...) {
+		if !seen[e] {
+			seen[e] = true
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// Sort orders recommendations by monthly savings, largest first, then by ID
+func Sort(recs []Recommendation) {
+	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].MonthlySavings != recs[j].MonthlySavings {
+			return recs[i].MonthlySavings > recs[j].MonthlySavings
+		}
+		return recs[i].ID < recs[j].ID
+	})
+}
+
+// TotalSavings sums the monthly savings of the recommendations
+func TotalSavings(recs []Recommendation) float64 {
+	total := 0.0
+	for _, rec := range recs {
+		total += rec.MonthlySavings
+	}
+	return total
+}
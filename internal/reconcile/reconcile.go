@@ -0,0 +1,164 @@
+// Package reconcile compares the cost expected from usage and the pricing catalog with
+// the billed cost, per service and day, to catch price changes, missing discounts and
+// usage that is not collected.
+package reconcile
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Statuses of a reconciled line
+const (
+	// StatusOK is billed cost within tolerance of the expected cost
+	StatusOK = "ok"
+
+	// StatusOverBilled is billed cost above the expected cost, e.g. a price increase or
+	// usage that is not collected
+	StatusOverBilled = "over-billed"
+
+	// StatusUnderBilled is billed cost below the expected cost, e.g. a discount, credit
+	// or free tier
+	StatusUnderBilled = "under-billed"
+
+	// StatusNotEstimated is billed cost without usage priced in the catalog
+	StatusNotEstimated = "not-estimated"
+
+	// StatusNotBilled is expected cost without billed cost
+	StatusNotBilled = "not-billed"
+)
+
+// Options sets when a difference between expected and billed cost is a discrepancy
+type Options struct {
+	// Tolerance is the share of the expected cost (or of the billed cost when nothing
+	// was expected) a difference may reach, e.g. 0.05
+	Tolerance float64
+
+	// MinDelta ignores differences smaller than this amount
+	MinDelta float64
+}
+
+// DefaultOptions tolerates differences of up to 5% or 1 currency unit
+func DefaultOptions() Options {
+	return Options{Tolerance: 0.05, MinDelta: 1}
+}
+
+// Line is the expected and billed cost of a service for one period, usually a day
+type Line struct {
+	Date     time.Time // Start of the period
+	Period   string    // Daily or Monthly
+	Service  string
+	Expected float64
+	Billed   float64
+	Currency string
+	Status   string
+}
+
+// Delta is the billed cost above the expected cost; negative when below
+func (l Line) Delta() float64 {
+	return l.Billed - l.Expected
+}
+
+// Discrepancy reports whether the line is outside the tolerance
+func (l Line) Discrepancy() bool {
+	return l.Status != StatusOK
+}
+
+// Reconcile matches expected and billed cost by period start, period and service. Cost
+// entries that are themselves estimated are not billed: services with estimated
+// entries among the billed cost, such as list-priced licenses, are left out.
+func Reconcile(expected, billed []providers.CostData, opts Options) []Line {
+	estimatedServices := make(map[string]bool)
+	for _, c := range billed {
+		if c.Estimated {
+			estimatedServices[c.Service] = true
+		}
+	}
+
+	type key struct {
+		date            string
+		period, service string
+	}
+	lines := make(map[key]*Line)
+	line := func(c providers.CostData) *Line {
+		k := key{c.StartTime.Format("2006-01-02"), c.Period, c.Service}
+		l, ok := lines[k]
+		if !ok {
+			l = &Line{Date: c.StartTime, Period: c.Period, Service: c.Service, Currency: c.Currency}
+			lines[k] = l
+		}
+		return l
+	}
+
+	for _, c := range billed {
+		if !estimatedServices[c.Service] {
+			line(c).Billed += c.Cost
+		}
+	}
+	for _, c := range expected {
+		if !estimatedServices[c.Service] {
+			line(c).Expected += c.Cost
+		}
+	}
+
+	result := make([]Line, 0, len(lines))
+	for _, l := range lines {
+		l.Status = status(*l, opts)
+		result = append(result, *l)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].Service < result[j].Service
+	})
+	return result
+}
+
+// status classifies a line; differences must exceed both the tolerance and MinDelta
+func status(l Line, opts Options) string {
+	delta := l.Delta()
+	base := l.Expected
+	if base == 0 {
+		base = l.Billed
+	}
+	if math.Abs(delta) <= opts.MinDelta || math.Abs(delta) <= opts.Tolerance*math.Abs(base) {
+		return StatusOK
+	}
+
+	switch {
+	case l.Expected == 0:
+		return StatusNotEstimated
+	case l.Billed == 0:
+		return StatusNotBilled
+	case delta > 0:
+		return StatusOverBilled
+	default:
+		return StatusUnderBilled
+	}
+}
+
+// Summary is the total expected and billed cost of reconciled lines
+type Summary struct {
+	Expected      float64
+	Billed        float64
+	Lines         int
+	Discrepancies int
+}
+
+// Summarize totals the lines
+func Summarize(lines []Line) Summary {
+	var s Summary
+	for _, l := range lines {
+		s.Expected += l.Expected
+		s.Billed += l.Billed
+		s.Lines++
+		if l.Discrepancy() {
+			s.Discrepancies++
+		}
+	}
+	return s
+}
@@ -0,0 +1,183 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/budget"
+	"github.com/olekukonko/tablewriter"
+)
+
+// BudgetCheck is the evaluation of every configured budget
+type BudgetCheck struct {
+	AsOf     time.Time // First day without cost; the month to date ends the day before
+	Statuses []budget.Status
+}
+
+// Output writes the budget check in the given format (table, json or markdown) to
+// stdout, or to filePath when it is set
+func (c *BudgetCheck) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return c.OutputTable(writer)
+	case "json":
+		return c.OutputJSON(writer)
+	case "markdown", "md":
+		return c.OutputMarkdown(writer)
+	default:
+		return fmt.Errorf("unsupported output format for budget check: %s (use table, json or markdown)", format)
+	}
+}
+
+var budgetColumns = []string{"Status", "Budget", "Provider", "Scope", "Basis", "Amount", "Actual", "Forecast", "Used", "Remaining"}
+
+// rows formats one row per budget; budgets that could not be evaluated show the error
+func (c *BudgetCheck) rows() [][]string {
+	rows := make([][]string, 0, len(c.Statuses))
+	for _, s := range c.Statuses {
+		b := s.Budget
+		row := []string{strings.ToUpper(s.Status), b.Name, b.Provider, b.Scope(), b.Basis, formatCurrency(b.Amount, s.Currency)}
+		if s.Status == budget.StatusError {
+			rows = append(rows, append(row, "n/a", s.Error, "", ""))
+			continue
+		}
+
+		projected := "n/a"
+		if s.Forecast != nil {
+			projected = fmt.Sprintf("%s (%s)", formatCurrency(s.Forecast.Expected, s.Currency), s.Model)
+		}
+		rows = append(rows, append(row,
+			formatCurrency(s.Actual, s.Currency),
+			projected,
+			formatPercent(s.Used),
+			formatCurrency(s.Remaining(), s.Currency),
+		))
+	}
+	return rows
+}
+
+// counts returns the number of budgets with each status
+func (c *BudgetCheck) counts() map[string]int {
+	counts := make(map[string]int)
+	for _, s := range c.Statuses {
+		counts[s.Status]++
+	}
+	return counts
+}
+
+func (c *BudgetCheck) summary() string {
+	counts := c.counts()
+	summary := fmt.Sprintf("%d budgets: %d critical, %d warning, %d ok", len(c.Statuses),
+		counts[budget.StatusCritical], counts[budget.StatusWarning], counts[budget.StatusOK])
+	if counts[budget.StatusError] > 0 {
+		summary += fmt.Sprintf(", %d could not be evaluated", counts[budget.StatusError])
+	}
+	return summary
+}
+
+// OutputTable writes the budget check as a plain-text table
+func (c *BudgetCheck) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "Budget check for %s, as of %s\n", c.AsOf.Format("January 2006"), c.AsOf.Format("2006-01-02"))
+	fmt.Fprintf(w, "%s\n\n", c.summary())
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(budgetColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+	})
+	table.AppendBulk(c.rows())
+	table.Render()
+
+	return nil
+}
+
+// OutputMarkdown writes the budget check as GitHub-flavored Markdown
+func (c *BudgetCheck) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Budget check for %s\n\n", c.AsOf.Format("January 2006"))
+	md.printf("- **As of:** %s\n", c.AsOf.Format("2006-01-02"))
+	md.printf("- **Result:** %s\n\n", c.summary())
+	md.table(budgetColumns, c.rows(), "lllllrrrrr")
+
+	return md.err
+}
+
+// OutputJSON writes the budget check as JSON
+func (c *BudgetCheck) OutputJSON(w io.Writer) error {
+	type jsonBudget struct {
+		Name        string   `json:"name"`
+		Status      string   `json:"status"`
+		Error       string   `json:"error,omitempty"`
+		Provider    string   `json:"provider"`
+		Account     string   `json:"account,omitempty"`
+		Service     string   `json:"service,omitempty"`
+		Team        string   `json:"team,omitempty"`
+		Basis       string   `json:"basis"`
+		Month       string   `json:"month"`
+		Currency    string   `json:"currency"`
+		Amount      float64  `json:"amount"`
+		WarnPct     float64  `json:"warnPct"`
+		CriticalPct float64  `json:"criticalPct"`
+		Actual      float64  `json:"actual"`
+		Forecast    *float64 `json:"forecast,omitempty"`
+		Model       string   `json:"model,omitempty"`
+		Used        float64  `json:"used"`
+		Remaining   float64  `json:"remaining"`
+	}
+
+	output := struct {
+		AsOf    string         `json:"asOf"`
+		Counts  map[string]int `json:"counts"`
+		Budgets []jsonBudget   `json:"budgets"`
+	}{
+		AsOf:    c.AsOf.Format("2006-01-02"),
+		Counts:  c.counts(),
+		Budgets: []jsonBudget{},
+	}
+	for _, s := range c.Statuses {
+		b := s.Budget
+		jb := jsonBudget{
+			Name:        b.Name,
+			Status:      s.Status,
+			Error:       s.Error,
+			Provider:    b.Provider,
+			Account:     b.Account,
+			Service:     b.Service,
+			Team:        b.Team,
+			Basis:       b.Basis,
+			Month:       s.Month.Format("2006-01"),
+			Currency:    s.Currency,
+			Amount:      b.Amount,
+			WarnPct:     b.WarnPct,
+			CriticalPct: b.CriticalPct,
+			Actual:      s.Actual,
+			Model:       s.Model,
+			Used:        s.Used,
+		}
+		if s.Status != budget.StatusError {
+			jb.Remaining = s.Remaining()
+		}
+		if s.Forecast != nil {
+			jb.Forecast = &s.Forecast.Expected
+		}
+		output.Budgets = append(output.Budgets, jb)
+	}
+
+	return WriteJSON(w, output)
+}
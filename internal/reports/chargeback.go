@@ -0,0 +1,208 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/allocation"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Chargeback is a per-team chargeback statement produced by the allocation rules
+type Chargeback struct {
+	*allocation.Statement
+}
+
+// Output writes the statement in the given format (table, json, markdown or csv) to
+// stdout, or to filePath when it is set
+func (c *Chargeback) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return c.OutputTable(writer)
+	case "json":
+		return c.OutputJSON(writer)
+	case "markdown", "md":
+		return c.OutputMarkdown(writer)
+	case "csv":
+		return c.OutputCSV(writer)
+	default:
+		return fmt.Errorf("unsupported output format for chargeback: %s (use table, json, markdown or csv)", format)
+	}
+}
+
+var (
+	chargebackTeamColumns = []string{"Team", "Cost", "Share", "Top Services"}
+	chargebackLineColumns = []string{"Team", "Provider", "Account", "Service", "Item", "Rule", "Method", "Cost", "Reason"}
+)
+
+func (c *Chargeback) period() string {
+	return fmt.Sprintf("%s to %s", c.Start.Format("2006-01-02"), c.End.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+func (c *Chargeback) totals() string {
+	share := func(v float64) string {
+		if c.Total == 0 {
+			return "n/a"
+		}
+		return formatPercent(v / c.Total)
+	}
+	return fmt.Sprintf("Total %s, allocated %s (%s), unallocated %s (%s)",
+		formatCurrency(c.Total, c.Currency),
+		formatCurrency(c.Allocated(), c.Currency), share(c.Allocated()),
+		formatCurrency(c.Unallocated, c.Currency), share(c.Unallocated))
+}
+
+func (c *Chargeback) teamRows() [][]string {
+	rows := make([][]string, 0, len(c.Teams))
+	for _, team := range c.Teams {
+		rows = append(rows, []string{team.Team, formatCurrency(team.Cost, c.Currency), formatPercent(team.Share), topServices(team.Services, 3)})
+	}
+	return rows
+}
+
+func (c *Chargeback) lineRows() [][]string {
+	rows := make([][]string, 0, len(c.Lines))
+	for _, line := range c.Lines {
+		rows = append(rows, []string{
+			line.Team, line.Provider, line.AccountID, line.Service, line.ItemName,
+			line.Rule, line.Method, formatCurrency(line.Cost, line.Currency), line.Reason,
+		})
+	}
+	return rows
+}
+
+// topServices lists the services with the most cost, e.g. "AmazonCloudWatch 80%"
+func topServices(services map[string]float64, n int) string {
+	total := 0.0
+	names := make([]string, 0, len(services))
+	for name, cost := range services {
+		names = append(names, name)
+		total += cost
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if services[names[i]] != services[names[j]] {
+			return services[names[i]] > services[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if total == 0 {
+			parts = append(parts, name)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", name, services[name]/total*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// OutputTable writes the statement as plain-text tables
+func (c *Chargeback) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "Chargeback statement, %s\n", c.period())
+	fmt.Fprintf(w, "%s\n\n", c.totals())
+
+	fmt.Fprintf(w, "=== Teams ===\n\n")
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(chargebackTeamColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT})
+	table.AppendBulk(c.teamRows())
+	table.Render()
+
+	fmt.Fprintf(w, "\n=== Line Items ===\n\n")
+	table = tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(chargebackLineColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT,
+	})
+	table.AppendBulk(c.lineRows())
+	table.Render()
+
+	return nil
+}
+
+// OutputMarkdown writes the statement as GitHub-flavored Markdown
+func (c *Chargeback) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Chargeback statement\n\n")
+	md.printf("- **Period:** %s\n", c.period())
+	md.printf("- **Result:** %s\n\n", c.totals())
+
+	md.printf("## Teams\n\n")
+	md.table(chargebackTeamColumns, c.teamRows(), "lrrl")
+	md.printf("## Line Items\n\n")
+	md.table(chargebackLineColumns, c.lineRows(), "lllllllrl")
+
+	return md.err
+}
+
+// OutputJSON writes the statement as JSON
+func (c *Chargeback) OutputJSON(w io.Writer) error {
+	output := struct {
+		StartDate   string                 `json:"startDate"`
+		EndDate     string                 `json:"endDate"`
+		Currency    string                 `json:"currency"`
+		Total       float64                `json:"total"`
+		Allocated   float64                `json:"allocated"`
+		Unallocated float64                `json:"unallocated"`
+		Teams       []allocation.TeamTotal `json:"teams"`
+		Lines       []allocation.Line      `json:"lines"`
+	}{
+		StartDate:   c.Start.Format("2006-01-02"),
+		EndDate:     c.End.Format("2006-01-02"),
+		Currency:    c.Currency,
+		Total:       c.Total,
+		Allocated:   c.Allocated(),
+		Unallocated: c.Unallocated,
+		Teams:       append([]allocation.TeamTotal{}, c.Teams...),
+		Lines:       append([]allocation.Line{}, c.Lines...),
+	}
+
+	return WriteJSON(w, output)
+}
+
+// chargebackCSVColumns is the column order of the CSV statement, one row per line item
+var chargebackCSVColumns = []string{
+	"start_date", "end_date", "team", "provider", "account_id", "service", "item_name",
+	"rule", "method", "cost", "currency", "reason",
+}
+
+// OutputCSV writes the line items of the statement as CSV, for finance systems
+func (c *Chargeback) OutputCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(chargebackCSVColumns); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+	for _, line := range c.Lines {
+		record := []string{
+			c.Start.Format("2006-01-02"), c.End.Format("2006-01-02"), line.Team, line.Provider, line.AccountID,
+			line.Service, line.ItemName, line.Rule, line.Method, formatCSVFloat(line.Cost), line.Currency, line.Reason,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,283 @@
+package reports
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// chartPalette is cycled through for chart series
+var chartPalette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+// chartIdleColor marks the unused share of a whole, such as unassigned licenses
+const chartIdleColor = "#d9d9d9"
+
+// svgChart is a rendered chart with its legend
+type svgChart struct {
+	Title  string
+	SVG    string
+	Legend []chartLegendItem
+}
+
+// chartLegendItem labels one series of a chart
+type chartLegendItem struct {
+	Label string
+	Color string
+}
+
+// dailyCostChart draws a line per service of the daily cost. Costs covering more than
+// a day (monthly license cost, for example) are left out so they don't show as spikes.
+func dailyCostChart(costs []providers.CostData) *svgChart {
+	series := make(map[string]map[string]float64)
+	daySet := make(map[string]bool)
+	for _, cost := range costs {
+		if !cost.EndTime.IsZero() && cost.EndTime.Sub(cost.StartTime) > 24*time.Hour {
+			continue
+		}
+		day := cost.StartTime.Format("2006-01-02")
+		if series[cost.Service] == nil {
+			series[cost.Service] = make(map[string]float64)
+		}
+		series[cost.Service][day] += cost.Cost
+		daySet[day] = true
+	}
+	if len(daySet) == 0 {
+		return nil
+	}
+
+	days := sortedKeys(daySet)
+	services := make([]string, 0, len(series))
+	for service := range series {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	maxValue := 0.0
+	for _, values := range series {
+		for _, v := range values {
+			maxValue = math.Max(maxValue, v)
+		}
+	}
+
+	const width, height = 720.0, 260.0
+	const left, right, top, bottom = 56.0, 16.0, 12.0, 28.0
+	plotW, plotH := width-left-right, height-top-bottom
+	maxValue = niceCeiling(maxValue)
+
+	x := func(i int) float64 {
+		if len(days) == 1 {
+			return left + plotW/2
+		}
+		return left + plotW*float64(i)/float64(len(days)-1)
+	}
+	y := func(v float64) float64 { return top + plotH - plotH*v/maxValue }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" role="img" aria-label="Daily cost per service">`, width, height)
+	writeYAxis(&b, left, width-right, y, maxValue)
+
+	// Label the first, middle and last day
+	for _, i := range uniqueInts(0, len(days)/2, len(days)-1) {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" class="axis">%s</text>`, x(i), height-8, days[i])
+	}
+
+	chart := &svgChart{Title: "Daily Cost by Service"}
+	for n, service := range services {
+		color := chartPalette[n%len(chartPalette)]
+		points := make([]string, 0, len(days))
+		for i, day := range days {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(series[service][day])))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"><title>%s</title></polyline>`,
+			color, strings.Join(points, " "), html.EscapeString(service))
+		chart.Legend = append(chart.Legend, chartLegendItem{Label: service, Color: color})
+	}
+	b.WriteString(`</svg>`)
+
+	chart.SVG = b.String()
+	return chart
+}
+
+// accountCostChart draws one bar per account, stacked by service
+func accountCostChart(costs []providers.CostData) *svgChart {
+	totals := make(map[string]map[string]float64)
+	serviceSet := make(map[string]bool)
+	for _, cost := range costs {
+		if totals[cost.AccountID] == nil {
+			totals[cost.AccountID] = make(map[string]float64)
+		}
+		totals[cost.AccountID][cost.Service] += cost.Cost
+		serviceSet[cost.Service] = true
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	accounts := make([]string, 0, len(totals))
+	for accountID := range totals {
+		accounts = append(accounts, accountID)
+	}
+	sort.Strings(accounts)
+	services := sortedKeys(serviceSet)
+
+	maxValue := 0.0
+	for _, byService := range totals {
+		sum := 0.0
+		for _, v := range byService {
+			sum += v
+		}
+		maxValue = math.Max(maxValue, sum)
+	}
+
+	const width, height = 720.0, 260.0
+	const left, right, top, bottom = 56.0, 16.0, 12.0, 28.0
+	plotW, plotH := width-left-right, height-top-bottom
+	maxValue = niceCeiling(maxValue)
+
+	y := func(v float64) float64 { return top + plotH - plotH*v/maxValue }
+	slot := plotW / float64(len(accounts))
+	barW := math.Min(slot*0.6, 80)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" role="img" aria-label="Cost per account">`, width, height)
+	writeYAxis(&b, left, width-right, y, maxValue)
+
+	for i, accountID := range accounts {
+		barX := left + slot*float64(i) + (slot-barW)/2
+		base := 0.0
+		for n, service := range services {
+			v := totals[accountID][service]
+			if v <= 0 {
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s / %s: %.2f</title></rect>`,
+				barX, y(base+v), barW, y(base)-y(base+v), chartPalette[n%len(chartPalette)],
+				html.EscapeString(accountID), html.EscapeString(service), v)
+			base += v
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" class="axis">%s</text>`,
+			barX+barW/2, height-8, html.EscapeString(accountID))
+	}
+	b.WriteString(`</svg>`)
+
+	chart := &svgChart{Title: "Cost by Account", SVG: b.String()}
+	for n, service := range services {
+		chart.Legend = append(chart.Legend, chartLegendItem{Label: service, Color: chartPalette[n%len(chartPalette)]})
+	}
+	return chart
+}
+
+// licenseUtilizationChart draws a donut of used licenses per type against the unused remainder
+func licenseUtilizationChart(usage []providers.UsageData) *svgChart {
+	var licenses []providers.UsageData
+	var used, total float64
+	for _, u := range usage {
+		if u.Service != "Licenses" {
+			continue
+		}
+		licenses = append(licenses, u)
+		used += u.Value
+		total += metadataFloat(u.Metadata, "totalLicenses")
+	}
+	if len(licenses) == 0 {
+		return nil
+	}
+	total = math.Max(total, used)
+	if total == 0 {
+		return nil
+	}
+
+	const size, radius, stroke = 220.0, 80.0, 28.0
+	circumference := 2 * math.Pi * radius
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" class="donut" role="img" aria-label="License utilization">`, size, size)
+	fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="none" stroke="%s" stroke-width="%.0f"/>`,
+		size/2, size/2, radius, chartIdleColor, stroke)
+
+	chart := &svgChart{Title: "License Utilization"}
+	offset := 0.0
+	for n, license := range licenses {
+		color := chartPalette[n%len(chartPalette)]
+		length := circumference * license.Value / total
+		fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="none" stroke="%s" stroke-width="%.0f" stroke-dasharray="%.2f %.2f" stroke-dashoffset="%.2f" transform="rotate(-90 %.0f %.0f)"><title>%s: %.0f used</title></circle>`,
+			size/2, size/2, radius, color, stroke, length, circumference-length, -offset, size/2, size/2,
+			html.EscapeString(license.Metric), license.Value)
+		offset += length
+		chart.Legend = append(chart.Legend, chartLegendItem{
+			Label: fmt.Sprintf("%s: %.0f / %.0f", license.Metric, license.Value, metadataFloat(license.Metadata, "totalLicenses")),
+			Color: color,
+		})
+	}
+	chart.Legend = append(chart.Legend, chartLegendItem{Label: fmt.Sprintf("Unused: %.0f", total-used), Color: chartIdleColor})
+
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="middle" class="donut-value">%.1f%%</text>`, size/2, size/2+4, 100*used/total)
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="middle" class="axis">utilized</text>`, size/2, size/2+22)
+	b.WriteString(`</svg>`)
+
+	chart.SVG = b.String()
+	return chart
+}
+
+// writeYAxis draws horizontal grid lines with value labels
+func writeYAxis(b *strings.Builder, left, right float64, y func(float64) float64, maxValue float64) {
+	const ticks = 4
+	for i := 0; i <= ticks; i++ {
+		v := maxValue * float64(i) / ticks
+		fmt.Fprintf(b, `<line x1="%.1f" x2="%.1f" y1="%.1f" y2="%.1f" class="grid"/>`, left, right, y(v), y(v))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end" class="axis">%s</text>`, left-6, y(v)+4, formatAxisValue(v))
+	}
+}
+
+// niceCeiling rounds v up to a 1, 2, 2.5 or 5 step so axis labels stay readable
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatAxisValue prints an axis label without needless decimals
+func formatAxisValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// sortedKeys returns the keys of a set in ascending order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// uniqueInts returns the given values without repeats, keeping their order
+func uniqueInts(values ...int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
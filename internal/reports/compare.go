@@ -0,0 +1,356 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Status of a line item between the two periods of a comparison
+const (
+	ComparisonNew         = "new"
+	ComparisonDisappeared = "disappeared"
+	ComparisonChanged     = "changed"
+	ComparisonUnchanged   = "unchanged"
+)
+
+// Deltas smaller than this are treated as unchanged
+const comparisonEpsilon = 0.005
+
+// Comparison holds the cost of two report periods, aligned line item by line item
+type Comparison struct {
+	Provider      string
+	Current       Period
+	Previous      Period
+	Currency      string // Shared currency of both periods, "" if mixed
+	CurrentTotal  float64
+	PreviousTotal float64
+	Delta         float64
+	DeltaPct      *float64 // nil when the previous period cost nothing
+
+	// Rows holds every line item, largest increase first
+	Rows []ComparisonRow
+}
+
+// ComparisonRow is one line item (provider, account, service and item) in both periods
+type ComparisonRow struct {
+	Provider  string   `json:"provider"`
+	AccountID string   `json:"accountId"`
+	Service   string   `json:"service"`
+	ItemName  string   `json:"itemName"`
+	Currency  string   `json:"currency"`
+	Current   float64  `json:"current"`
+	Previous  float64  `json:"previous"`
+	Delta     float64  `json:"delta"`
+	DeltaPct  *float64 `json:"deltaPct"` // nil for new line items
+	Status    string   `json:"status"`
+}
+
+type comparisonKey struct {
+	provider, account, service, item string
+}
+
+// CompareReports aligns the cost entries of two reports by provider, account,
+// service and item name, and computes the change from previous to current
+func CompareReports(current, previous *Report) *Comparison {
+	c := &Comparison{
+		Provider: current.ProviderName,
+		Current:  Period{Start: current.StartDate, End: current.EndDate},
+		Previous: Period{Start: previous.StartDate, End: previous.EndDate},
+	}
+
+	rows := make(map[comparisonKey]*ComparisonRow)
+	var order []comparisonKey
+	add := func(report *Report, apply func(*ComparisonRow, float64)) {
+		for _, cost := range report.CostData {
+			key := comparisonKey{report.ProviderName, cost.AccountID, cost.Service, cost.ItemName}
+			row, ok := rows[key]
+			if !ok {
+				row = &ComparisonRow{
+					Provider:  report.ProviderName,
+					AccountID: cost.AccountID,
+					Service:   cost.Service,
+					ItemName:  cost.ItemName,
+					Currency:  cost.Currency,
+				}
+				rows[key] = row
+				order = append(order, key)
+			}
+			apply(row, cost.Cost)
+		}
+	}
+	add(current, func(row *ComparisonRow, cost float64) { row.Current += cost })
+	add(previous, func(row *ComparisonRow, cost float64) { row.Previous += cost })
+
+	for _, key := range order {
+		row := rows[key]
+		row.Delta = row.Current - row.Previous
+		row.DeltaPct = percentChange(row.Previous, row.Current)
+
+		switch {
+		case row.Previous == 0 && row.Current != 0:
+			row.Status = ComparisonNew
+		case row.Current == 0 && row.Previous != 0:
+			row.Status = ComparisonDisappeared
+		case math.Abs(row.Delta) < comparisonEpsilon:
+			row.Status = ComparisonUnchanged
+		default:
+			row.Status = ComparisonChanged
+		}
+
+		c.CurrentTotal += row.Current
+		c.PreviousTotal += row.Previous
+		c.Rows = append(c.Rows, *row)
+	}
+
+	sort.SliceStable(c.Rows, func(i, j int) bool {
+		if c.Rows[i].Delta != c.Rows[j].Delta {
+			return c.Rows[i].Delta > c.Rows[j].Delta
+		}
+		return c.Rows[i].label() < c.Rows[j].label()
+	})
+
+	c.Delta = c.CurrentTotal - c.PreviousTotal
+	c.DeltaPct = percentChange(c.PreviousTotal, c.CurrentTotal)
+
+	costs := make([]providers.CostData, 0, len(current.CostData)+len(previous.CostData))
+	costs = append(costs, current.CostData...)
+	costs = append(costs, previous.CostData...)
+	c.Currency = reportCurrency(costs)
+	return c
+}
+
+// TopIncreases returns at most n line items whose cost went up, largest first
+func (c *Comparison) TopIncreases(n int) []ComparisonRow {
+	var rows []ComparisonRow
+	for _, row := range c.Rows {
+		if row.Delta < comparisonEpsilon || len(rows) == n {
+			break
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// RowsWithStatus returns the line items with the given status
+func (c *Comparison) RowsWithStatus(status string) []ComparisonRow {
+	var rows []ComparisonRow
+	for _, row := range c.Rows {
+		if row.Status == status {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// label identifies the line item in text output
+func (row ComparisonRow) label() string {
+	return fmt.Sprintf("%s/%s/%s", row.AccountID, row.Service, row.ItemName)
+}
+
+// percentChange returns the change from previous to current as a ratio, or nil
+// when there is nothing to compare against
+func percentChange(previous, current float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	pct := (current - previous) / math.Abs(previous)
+	return &pct
+}
+
+// Output writes the comparison in the given format (table, json or markdown) to
+// stdout, or to filePath when it is set. top limits the top increases shown.
+func (c *Comparison) Output(format, filePath string, top int) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return c.OutputTable(writer, top)
+	case "json":
+		return c.OutputJSON(writer, top)
+	case "markdown", "md":
+		return c.OutputMarkdown(writer, top)
+	default:
+		return fmt.Errorf("unsupported output format for compare: %s (use table, json or markdown)", format)
+	}
+}
+
+// OutputJSON writes the comparison as JSON
+func (c *Comparison) OutputJSON(w io.Writer, top int) error {
+	type jsonPeriod struct {
+		Label     string  `json:"label"`
+		StartDate string  `json:"startDate"`
+		EndDate   string  `json:"endDate"`
+		Total     float64 `json:"total"`
+	}
+	period := func(p Period, total float64) jsonPeriod {
+		return jsonPeriod{
+			Label:     p.String(),
+			StartDate: p.Start.Format("2006-01-02"),
+			EndDate:   p.End.Format("2006-01-02"),
+			Total:     total,
+		}
+	}
+
+	output := struct {
+		Provider     string          `json:"provider"`
+		Period       jsonPeriod      `json:"period"`
+		Against      jsonPeriod      `json:"against"`
+		Currency     string          `json:"currency"`
+		Delta        float64         `json:"delta"`
+		DeltaPct     *float64        `json:"deltaPct"`
+		TopIncreases []ComparisonRow `json:"topIncreases"`
+		New          []ComparisonRow `json:"new"`
+		Disappeared  []ComparisonRow `json:"disappeared"`
+		Rows         []ComparisonRow `json:"rows"`
+	}{
+		Provider:     c.Provider,
+		Period:       period(c.Current, c.CurrentTotal),
+		Against:      period(c.Previous, c.PreviousTotal),
+		Currency:     c.Currency,
+		Delta:        c.Delta,
+		DeltaPct:     c.DeltaPct,
+		TopIncreases: nonNilRows(c.TopIncreases(top)),
+		New:          nonNilRows(c.RowsWithStatus(ComparisonNew)),
+		Disappeared:  nonNilRows(c.RowsWithStatus(ComparisonDisappeared)),
+		Rows:         nonNilRows(c.Rows),
+	}
+
+	return WriteJSON(w, output)
+}
+
+// OutputTable writes the comparison as plain-text tables
+func (c *Comparison) OutputTable(w io.Writer, top int) error {
+	fmt.Fprintf(w, "Cost comparison for %s\n", c.Provider)
+	fmt.Fprintf(w, "Period:  %s  %s\n", c.Current, formatCurrency(c.CurrentTotal, c.Currency))
+	fmt.Fprintf(w, "Against: %s  %s\n", c.Previous, formatCurrency(c.PreviousTotal, c.Currency))
+	fmt.Fprintf(w, "Change:  %s (%s)\n", formatSignedCurrency(c.Delta, c.Currency), formatDeltaPct(c.DeltaPct))
+
+	sections := []struct {
+		title string
+		rows  []ComparisonRow
+	}{
+		{"Top Increases", c.TopIncreases(top)},
+		{"New Line Items", c.RowsWithStatus(ComparisonNew)},
+		{"Disappeared Line Items", c.RowsWithStatus(ComparisonDisappeared)},
+		{"All Line Items", c.Rows},
+	}
+	for _, section := range sections {
+		if len(section.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n=== %s ===\n\n", section.title)
+
+		table := tablewriter.NewWriter(&writerAdapter{w: w})
+		table.SetHeader(comparisonColumns)
+		table.SetAutoWrapText(false)
+		table.SetColumnAlignment([]int{
+			tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_LEFT,
+		})
+		for _, row := range section.rows {
+			table.Append(row.cells())
+		}
+		table.Render()
+	}
+
+	return nil
+}
+
+// OutputMarkdown writes the comparison as GitHub-flavored Markdown
+func (c *Comparison) OutputMarkdown(w io.Writer, top int) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Cost comparison for %s\n\n", c.Provider)
+	md.table([]string{"", "Period", "Cost"}, [][]string{
+		{"Period", c.Current.String(), formatCurrency(c.CurrentTotal, c.Currency)},
+		{"Against", c.Previous.String(), formatCurrency(c.PreviousTotal, c.Currency)},
+		{"**Change**", "", fmt.Sprintf("**%s** (%s)", formatSignedCurrency(c.Delta, c.Currency), formatDeltaPct(c.DeltaPct))},
+	}, "llr")
+
+	sections := []struct {
+		title string
+		rows  []ComparisonRow
+	}{
+		{"Top increases", c.TopIncreases(top)},
+		{"New line items", c.RowsWithStatus(ComparisonNew)},
+		{"Disappeared line items", c.RowsWithStatus(ComparisonDisappeared)},
+	}
+	for _, section := range sections {
+		if len(section.rows) == 0 {
+			continue
+		}
+		md.printf("## %s\n\n", section.title)
+		md.table(comparisonColumns, comparisonCells(section.rows), "lllrrrrl")
+	}
+
+	md.printf("## All line items\n\n")
+	md.detailTable(fmt.Sprintf("%d line items", len(c.Rows)), comparisonColumns, comparisonCells(c.Rows), "lllrrrrl")
+
+	return md.err
+}
+
+var comparisonColumns = []string{"Account", "Service", "Item", "Previous", "Current", "Change", "Change %", "Status"}
+
+// cells formats the row for the comparison tables
+func (row ComparisonRow) cells() []string {
+	return []string{
+		row.AccountID,
+		row.Service,
+		row.ItemName,
+		formatCurrency(row.Previous, row.Currency),
+		formatCurrency(row.Current, row.Currency),
+		formatSignedCurrency(row.Delta, row.Currency),
+		formatDeltaPct(row.DeltaPct),
+		row.Status,
+	}
+}
+
+func comparisonCells(rows []ComparisonRow) [][]string {
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = row.cells()
+	}
+	return cells
+}
+
+// formatSignedCurrency formats a delta with an explicit + for increases
+func formatSignedCurrency(amount float64, currency string) string {
+	if amount > 0 {
+		return "+" + formatCurrency(amount, currency)
+	}
+	return formatCurrency(amount, currency)
+}
+
+// formatDeltaPct formats a change ratio as a signed percentage, "n/a" without a baseline
+func formatDeltaPct(pct *float64) string {
+	if pct == nil {
+		return "n/a"
+	}
+	if *pct > 0 {
+		return "+" + formatPercent(*pct)
+	}
+	return formatPercent(*pct)
+}
+
+// nonNilRows keeps empty lists as [] rather than null in JSON
+func nonNilRows(rows []ComparisonRow) []ComparisonRow {
+	if rows == nil {
+		return []ComparisonRow{}
+	}
+	return rows
+}
@@ -0,0 +1,209 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV sections, in the order they are written
+const (
+	CSVSectionUsage    = "usage"
+	CSVSectionCost     = "cost"
+	CSVSectionLicenses = "licenses"
+	CSVSectionSections = "sections"
+)
+
+// Column order of each CSV section. These are part of the output contract, so new
+// columns must only ever be appended.
+var (
+	usageCSVColumns = []string{
+		"provider", "service", "metric", "value", "unit", "timestamp", "account_id",
+	}
+	costCSVColumns = []string{
+		"provider", "account_id", "service", "item_name", "start_date", "end_date", "period",
+		"cost", "currency", "quantity", "usage_unit", "region", "description",
+		"estimated",
+	}
+	licenseCSVColumns = []string{
+		"provider", "license_type", "used", "total", "utilization_pct",
+	}
+	sectionCSVColumns = []string{
+		"provider", "section", "kind", "table", "row", "column", "type", "unit", "value",
+	}
+)
+
+// csvSection is a named table of CSV records including its header row
+type csvSection struct {
+	name    string
+	records [][]string
+}
+
+// csvSections builds the usage, cost, license and report-section tables. Every
+// section is always present so consumers see the same files and headers each run.
+func (r *Report) csvSections() []csvSection {
+	usage := [][]string{usageCSVColumns}
+	licenses := [][]string{licenseCSVColumns}
+
+	for _, u := range r.UsageData {
+		if u.Service == "Licenses" {
+			licenses = append(licenses, []string{
+				r.ProviderName,
+				strings.TrimSuffix(u.Metric, " Licenses"),
+				formatCSVFloat(u.Value),
+				formatCSVFloat(metadataFloat(u.Metadata, "totalLicenses")),
+				formatCSVFloat(metadataFloat(u.Metadata, "utilizationPct")),
+			})
+			continue
+		}
+
+		usage = append(usage, []string{
+			r.ProviderName,
+			u.Service,
+			u.Metric,
+			formatCSVFloat(u.Value),
+			u.Unit,
+			u.Timestamp.UTC().Format(time.RFC3339),
+			metadataString(u.Metadata, "accountId"),
+		})
+	}
+
+	cost := [][]string{costCSVColumns}
+	for _, c := range r.CostData {
+		cost = append(cost, []string{
+			r.ProviderName,
+			c.AccountID,
+			c.Service,
+			c.ItemName,
+			c.StartTime.Format("2006-01-02"),
+			c.EndTime.Format("2006-01-02"),
+			c.Period,
+			formatCSVFloat(c.Cost),
+			c.Currency,
+			formatCSVFloat(c.Quantity),
+			c.UsageUnit,
+			c.Region,
+			c.Description,
+			strconv.FormatBool(c.Estimated),
+		})
+	}
+
+	// Report sections in long format, one value per row
+	sections := [][]string{sectionCSVColumns}
+	for _, section := range r.orderedSections() {
+		for _, cell := range sectionCells(section) {
+			sections = append(sections, []string{
+				r.ProviderName,
+				cell.Section,
+				cell.Kind,
+				cell.Table,
+				strconv.Itoa(cell.Row),
+				cell.Column,
+				string(cell.Type),
+				cell.Unit,
+				rawSectionValue(cell.Value, cell.Type),
+			})
+		}
+	}
+
+	return []csvSection{
+		{name: CSVSectionUsage, records: usage},
+		{name: CSVSectionCost, records: cost},
+		{name: CSVSectionLicenses, records: licenses},
+		{name: CSVSectionSections, records: sections},
+	}
+}
+
+// OutputCSV writes every section to a single stream. Each section starts with a
+// "# <section>" marker line followed by its header row, and sections are separated
+// by a blank line.
+func (r *Report) OutputCSV(w io.Writer) error {
+	for i, section := range r.csvSections() {
+		if i > 0 {
+			fmt.Fprint(w, "\r\n")
+		}
+		fmt.Fprintf(w, "# %s\r\n", section.name)
+
+		if err := writeCSVRecords(w, section.records); err != nil {
+			return fmt.Errorf("error writing %s CSV section: %w", section.name, err)
+		}
+	}
+
+	return nil
+}
+
+// OutputCSVFiles writes each section to its own RFC 4180 file next to basePath:
+// report.csv becomes report-usage.csv, report-cost.csv, report-licenses.csv and
+// report-sections.csv.
+// It returns the paths written.
+func (r *Report) OutputCSVFiles(basePath string) ([]string, error) {
+	ext := filepath.Ext(basePath)
+	if ext == "" {
+		ext = ".csv"
+	}
+	base := strings.TrimSuffix(basePath, filepath.Ext(basePath))
+
+	var paths []string
+	for _, section := range r.csvSections() {
+		path := fmt.Sprintf("%s-%s%s", base, section.name, ext)
+
+		file, err := os.Create(path)
+		if err != nil {
+			return paths, fmt.Errorf("failed to create CSV file %s: %w", path, err)
+		}
+
+		err = writeCSVRecords(file, section.records)
+		closeErr := file.Close()
+		if err != nil {
+			return paths, fmt.Errorf("error writing CSV file %s: %w", path, err)
+		}
+		if closeErr != nil {
+			return paths, fmt.Errorf("error closing CSV file %s: %w", path, closeErr)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// writeCSVRecords writes records with CRLF line endings as required by RFC 4180
+func writeCSVRecords(w io.Writer, records [][]string) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// formatCSVFloat formats numbers without exponents or trailing zeros
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// metadataFloat reads a numeric metadata value, which may be an int before JSON round trips
+func metadataFloat(metadata map[string]interface{}, key string) float64 {
+	switch v := metadata[key].(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// metadataString reads a string metadata value
+func metadataString(metadata map[string]interface{}, key string) string {
+	if v, ok := metadata[key].(string); ok {
+		return v
+	}
+	return ""
+}
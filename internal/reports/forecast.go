@@ -0,0 +1,189 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ilhicas/observability-cost-center/internal/forecast"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Forecast is a spend forecast built from the daily cost of a report
+type Forecast struct {
+	Provider string
+	Currency string
+	*forecast.Result
+}
+
+// NewForecast forecasts month-end and next-month spend from the report's cost data.
+// The report period is the history: it should end on the last day with complete cost.
+// Cost reported per month or other multi-day period is refused, as it has no daily shape.
+func (r *Report) NewForecast(models []forecast.Model, opts forecast.Options) (*Forecast, error) {
+	if err := forecast.CheckDaily(r.CostData); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.ProviderName, err)
+	}
+	history := forecast.DailySeries(r.CostData, r.StartDate, r.EndDate)
+	result, err := forecast.Run(history, models, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Forecast{Provider: r.ProviderName, Currency: reportCurrency(r.CostData), Result: result}, nil
+}
+
+// Output writes the forecast in the given format (table, json or markdown) to stdout,
+// or to filePath when it is set
+func (f *Forecast) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return f.OutputTable(writer)
+	case "json":
+		return f.OutputJSON(writer)
+	case "markdown", "md":
+		return f.OutputMarkdown(writer)
+	default:
+		return fmt.Errorf("unsupported output format for forecast: %s (use table, json or markdown)", format)
+	}
+}
+
+var forecastColumns = []string{"Model", "Month-End", "Month-End Range", "Next Month", "Next Month Range", "Backtest MAE", "Backtest MAPE", "Bias"}
+
+// rows formats one row per model; the best model is marked with *
+func (f *Forecast) rows() [][]string {
+	rows := make([][]string, 0, len(f.Models))
+	for _, mr := range f.Models {
+		name := mr.Model
+		if name == f.Best {
+			name += " *"
+		}
+		if mr.Error != "" {
+			rows = append(rows, []string{name, "n/a", mr.Error, "", "", "", "", ""})
+			continue
+		}
+
+		mae, mape, bias := "n/a", "n/a", "n/a"
+		if mr.Backtest.Runs > 0 {
+			mae = formatCurrency(mr.Backtest.MAE, f.Currency)
+			mape = formatPercent(mr.Backtest.MAPE)
+			bias = formatDeltaPct(&mr.Backtest.Bias)
+		}
+		rows = append(rows, []string{
+			name,
+			formatCurrency(mr.MonthEnd.Expected, f.Currency),
+			f.formatRange(mr.MonthEnd),
+			formatCurrency(mr.NextMonth.Expected, f.Currency),
+			f.formatRange(mr.NextMonth),
+			mae,
+			mape,
+			bias,
+		})
+	}
+	return rows
+}
+
+func (f *Forecast) formatRange(p forecast.Projection) string {
+	return fmt.Sprintf("%s - %s", formatCurrency(p.Lower, f.Currency), formatCurrency(p.Upper, f.Currency))
+}
+
+// OutputTable writes the forecast as a plain-text table
+func (f *Forecast) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "Spend forecast for %s\n", f.Provider)
+	fmt.Fprintf(w, "History: %s to %s (%d days)\n", f.History[0].Date.Format("2006-01-02"),
+		f.History[len(f.History)-1].Date.Format("2006-01-02"), len(f.History))
+	fmt.Fprintf(w, "%s to date: %s\n", f.Month.Format("January 2006"), formatCurrency(f.MonthToDate, f.Currency))
+	fmt.Fprintf(w, "Ranges are %.0f%% confidence bands; * marks the model with the lowest backtest error\n\n", f.Confidence*100)
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(forecastColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+	})
+	table.AppendBulk(f.rows())
+	table.Render()
+
+	return nil
+}
+
+// OutputMarkdown writes the forecast as GitHub-flavored Markdown
+func (f *Forecast) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Spend forecast for %s\n\n", f.Provider)
+	md.printf("- **History:** %s to %s (%d days)\n", f.History[0].Date.Format("2006-01-02"),
+		f.History[len(f.History)-1].Date.Format("2006-01-02"), len(f.History))
+	md.printf("- **%s to date:** %s\n", f.Month.Format("January 2006"), formatCurrency(f.MonthToDate, f.Currency))
+	md.printf("- **Confidence band:** %.0f%%\n\n", f.Confidence*100)
+
+	md.table(forecastColumns, f.rows(), "lrrrrrrr")
+	md.printf("\\* lowest backtest error\n")
+
+	return md.err
+}
+
+// OutputJSON writes the forecast as JSON, including the daily forecast of every model
+func (f *Forecast) OutputJSON(w io.Writer) error {
+	type jsonPoint struct {
+		Date  string  `json:"date"`
+		Value float64 `json:"value"`
+	}
+	type jsonModel struct {
+		Model     string              `json:"model"`
+		Error     string              `json:"error,omitempty"`
+		MonthEnd  forecast.Projection `json:"monthEnd"`
+		NextMonth forecast.Projection `json:"nextMonth"`
+		Backtest  forecast.Backtest   `json:"backtest"`
+		Daily     []jsonPoint         `json:"daily,omitempty"`
+	}
+	points := func(series []forecast.Point) []jsonPoint {
+		out := make([]jsonPoint, len(series))
+		for i, p := range series {
+			out[i] = jsonPoint{Date: p.Date.Format("2006-01-02"), Value: p.Value}
+		}
+		return out
+	}
+
+	output := struct {
+		Provider    string      `json:"provider"`
+		Currency    string      `json:"currency"`
+		AsOf        string      `json:"asOf"`
+		Month       string      `json:"month"`
+		MonthToDate float64     `json:"monthToDate"`
+		Confidence  float64     `json:"confidence"`
+		Best        string      `json:"best"`
+		Models      []jsonModel `json:"models"`
+		History     []jsonPoint `json:"history"`
+	}{
+		Provider:    f.Provider,
+		Currency:    f.Currency,
+		AsOf:        f.AsOf.Format("2006-01-02"),
+		Month:       f.Month.Format("2006-01"),
+		MonthToDate: f.MonthToDate,
+		Confidence:  f.Confidence,
+		Best:        f.Best,
+		History:     points(f.History),
+	}
+	for _, mr := range f.Models {
+		output.Models = append(output.Models, jsonModel{
+			Model:     mr.Model,
+			Error:     mr.Error,
+			MonthEnd:  mr.MonthEnd,
+			NextMonth: mr.NextMonth,
+			Backtest:  mr.Backtest,
+			Daily:     points(mr.Daily),
+		})
+	}
+
+	return WriteJSON(w, output)
+}
@@ -22,14 +22,6 @@ const (
 
 // Report defines the structure of a report
 
-// AppendCustomSection adds a custom section to the report
-func (r *Report) AppendCustomSection(title, content string) {
-	if r.CustomSections == nil {
-		r.CustomSections = make(map[string]string)
-	}
-	r.CustomSections[title] = content
-}
-
 // GenerateReport generates a report based on the specified report type
 func (rg *ReportGenerator) GenerateReport(reportType ReportType, start, end time.Time) (*Report, error) {
 	report := &Report{
@@ -84,6 +76,23 @@ func (rg *ReportGenerator) GenerateFullReport(start, end time.Time) (*Report, er
 // Output formats and outputs the report according to the specified format
 // and optionally writes to a file if filePath is provided
 func (r *Report) Output(format string, filePath string) error {
+	// A template replaces the selected format
+	if r.Options.Template != "" {
+		format = "template"
+	}
+
+	// CSV written to a path is split into one file per section
+	if format == "csv" && filePath != "" {
+		_, err := r.OutputCSVFiles(filePath)
+		return err
+	}
+
+	// Parquet has one schema per file, so it always writes usage and cost files to a path
+	if format == "parquet" {
+		_, err := r.OutputParquet(filePath)
+		return err
+	}
+
 	var writer io.Writer = os.Stdout
 
 	// If filePath is provided, create and use the file
@@ -97,10 +106,20 @@ func (r *Report) Output(format string, filePath string) error {
 	}
 
 	switch format {
+	case "template":
+		return r.OutputTemplate(writer, r.Options.Template)
 	case "json":
-		return r.outputJSON(writer)
+		return r.OutputJSON(writer)
 	case "csv":
-		return r.outputCSV(writer)
+		return r.OutputCSV(writer)
+	case "markdown", "md":
+		return r.OutputMarkdown(writer)
+	case "html":
+		return r.OutputHTML(writer)
+	case "xlsx":
+		return r.OutputXLSX(writer)
+	case "openmetrics", "prometheus":
+		return r.OutputOpenMetrics(writer)
 	case "table":
 		return r.outputAsTable(writer)
 	case "summary":
@@ -110,20 +129,6 @@ func (r *Report) Output(format string, filePath string) error {
 	}
 }
 
-// outputJSON outputs the report in JSON format
-func (r *Report) outputJSON(w io.Writer) error {
-	// In a real implementation, use JSON marshaling
-	fmt.Fprintln(w, "JSON output format not yet implemented")
-	return nil
-}
-
-// outputCSV outputs the report in CSV format
-func (r *Report) outputCSV(w io.Writer) error {
-	// In a real implementation, write CSV data
-	fmt.Fprintln(w, "CSV output format not yet implemented")
-	return nil
-}
-
 // outputTable outputs the report in a tabular format
 func (r *Report) outputTable(w io.Writer) error {
 	// Add debug information to help diagnose issues
@@ -312,12 +317,10 @@ func (r *Report) outputTable(w io.Writer) error {
 		fmt.Fprintf(w, "%-20s %-10.4f %s %-10.2f events\n", "GRAND TOTAL", grandTotalCost, currency, grandTotalUsage)
 	}
 
-	// After all standard sections, output any custom sections
-	if len(r.CustomSections) > 0 {
-		for title, content := range r.CustomSections {
-			fmt.Fprintf(w, "\n\n=== %s ===\n\n", title)
-			fmt.Fprintln(w, content)
-		}
+	// After all standard sections, output the report sections
+	for _, section := range r.orderedSections() {
+		fmt.Fprintln(w)
+		writeSectionText(w, section)
 	}
 
 	return nil
@@ -503,12 +506,11 @@ func (r *Report) outputAsTable(w io.Writer) error {
 		}
 	}
 
-	// After all standard sections, output any custom sections
-	if len(r.CustomSections) > 0 {
-		fmt.Fprintln(w, "\n")
-		for title, content := range r.CustomSections {
-			fmt.Fprintf(w, "\n=== %s ===\n\n", title)
-			fmt.Fprintln(w, content)
+	// After all standard sections, output the report sections
+	if len(r.Sections) > 0 {
+		fmt.Fprint(w, "\n\n")
+		for _, section := range r.orderedSections() {
+			writeSectionText(w, section)
 		}
 	}
 
@@ -0,0 +1,261 @@
+package reports
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+//go:embed templates/report.html
+var htmlReportTemplate string
+
+// htmlReport is the data handed to the HTML template
+type htmlReport struct {
+	Title       string
+	Provider    string
+	Period      string
+	GeneratedAt string
+	TotalCost   string
+	UsageCount  int
+	CostCount   int
+	Charts      []htmlChart
+	Tables      []htmlTable
+	Sections    []htmlSection
+}
+
+type htmlChart struct {
+	Title  string
+	SVG    template.HTML
+	Legend []chartLegendItem
+}
+
+type htmlTable struct {
+	Title   string
+	Columns []htmlColumn
+	Rows    [][]htmlCell
+}
+
+type htmlColumn struct {
+	Name    string
+	Numeric bool
+}
+
+// htmlCell holds the displayed text and, for numeric columns, the raw value used for sorting
+type htmlCell struct {
+	Text string
+	Sort string
+}
+
+type htmlSection struct {
+	Title    string
+	Summary  []htmlField
+	Findings []htmlFinding
+	Tables   []htmlTable
+	Notes    []string
+}
+
+type htmlField struct {
+	Label string
+	Value string
+}
+
+type htmlFinding struct {
+	Severity string
+	Text     string
+	Detail   string
+}
+
+// OutputHTML renders the report as a single self-contained HTML page with inline SVG
+// charts and sortable tables. It loads no external scripts, styles or fonts.
+func (r *Report) OutputHTML(w io.Writer) error {
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("error parsing HTML template: %w", err)
+	}
+
+	if err := tmpl.Execute(w, r.htmlView()); err != nil {
+		return fmt.Errorf("error rendering HTML report: %w", err)
+	}
+	return nil
+}
+
+// htmlView builds the template data for the report
+func (r *Report) htmlView() htmlReport {
+	view := htmlReport{
+		Title:       fmt.Sprintf("%s Report for %s", titleCase(r.ReportType), r.ProviderName),
+		Provider:    r.ProviderName,
+		Period:      fmt.Sprintf("%s to %s", r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02")),
+		GeneratedAt: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		TotalCost:   fmt.Sprintf("%.2f %s", r.TotalCost, reportCurrency(r.CostData)),
+		UsageCount:  len(r.UsageData),
+		CostCount:   len(r.CostData),
+	}
+
+	showUsage := r.ReportType == "usage" || r.ReportType == "full"
+	showCost := r.ReportType == "cost" || r.ReportType == "full"
+
+	// Charts
+	var charts []*svgChart
+	if showCost {
+		charts = append(charts, dailyCostChart(r.CostData), accountCostChart(r.CostData))
+	}
+	charts = append(charts, licenseUtilizationChart(r.UsageData))
+	for _, chart := range charts {
+		if chart == nil {
+			continue
+		}
+		view.Charts = append(view.Charts, htmlChart{
+			Title:  chart.Title,
+			SVG:    template.HTML(chart.SVG), // built from escaped values in charts.go
+			Legend: chart.Legend,
+		})
+	}
+
+	// Tables
+	if showCost && len(r.CostData) > 0 {
+		view.Tables = append(view.Tables, accountSummaryTable(r.CostData), costTable(r.CostData))
+	}
+	if showUsage && len(r.UsageData) > 0 {
+		view.Tables = append(view.Tables, usageTable(r.UsageData))
+	}
+
+	for _, section := range r.orderedSections() {
+		view.Sections = append(view.Sections, sectionToHTML(section))
+	}
+
+	return view
+}
+
+// accountSummaryTable totals cost per account
+func accountSummaryTable(costs []providers.CostData) htmlTable {
+	totals := make(map[string]float64)
+	currencies := make(map[string]string)
+	for _, cost := range costs {
+		totals[cost.AccountID] += cost.Cost
+		currencies[cost.AccountID] = cost.Currency
+	}
+	accountIDs := make([]string, 0, len(totals))
+	for accountID := range totals {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	table := htmlTable{
+		Title:   "Account Cost Summary",
+		Columns: []htmlColumn{{Name: "Account ID"}, {Name: "Total Cost", Numeric: true}, {Name: "Currency"}},
+	}
+	for _, accountID := range accountIDs {
+		table.Rows = append(table.Rows, []htmlCell{
+			{Text: accountID},
+			numericCell(totals[accountID], "%.4f"),
+			{Text: currencies[accountID]},
+		})
+	}
+	return table
+}
+
+// costTable lists every cost entry
+func costTable(costs []providers.CostData) htmlTable {
+	table := htmlTable{
+		Title: "Cost Details",
+		Columns: []htmlColumn{
+			{Name: "Date"}, {Name: "Account ID"}, {Name: "Service"}, {Name: "Item"},
+			{Name: "Cost", Numeric: true}, {Name: "Currency"}, {Name: "Quantity", Numeric: true},
+			{Name: "Unit"}, {Name: "Description"},
+		},
+	}
+	for _, cost := range costs {
+		table.Rows = append(table.Rows, []htmlCell{
+			{Text: cost.StartTime.Format("2006-01-02")},
+			{Text: cost.AccountID},
+			{Text: cost.Service},
+			{Text: cost.ItemName},
+			numericCell(cost.Cost, "%.4f"),
+			{Text: cost.Currency},
+			numericCell(cost.Quantity, "%.2f"),
+			{Text: cost.UsageUnit},
+			{Text: cost.Description},
+		})
+	}
+	return table
+}
+
+// usageTable lists every usage entry
+func usageTable(usage []providers.UsageData) htmlTable {
+	table := htmlTable{
+		Title: "Usage",
+		Columns: []htmlColumn{
+			{Name: "Timestamp"}, {Name: "Service"}, {Name: "Metric"},
+			{Name: "Value", Numeric: true}, {Name: "Unit"},
+		},
+	}
+	for _, u := range usage {
+		table.Rows = append(table.Rows, []htmlCell{
+			{Text: u.Timestamp.Format("2006-01-02 15:04:05")},
+			{Text: u.Service},
+			{Text: u.Metric},
+			numericCell(u.Value, "%.4f"),
+			{Text: u.Unit},
+		})
+	}
+	return table
+}
+
+// sectionToHTML converts a provider section, keeping raw values of numeric
+// columns for sorting
+func sectionToHTML(section providers.Section) htmlSection {
+	hs := htmlSection{Title: section.Title, Notes: section.Notes}
+	for _, field := range section.Summary {
+		hs.Summary = append(hs.Summary, htmlField{
+			Label: field.Label,
+			Value: formatSectionValue(field.Value, field.Type, field.Unit),
+		})
+	}
+	for _, f := range section.Findings {
+		hs.Findings = append(hs.Findings, htmlFinding{Severity: f.Severity, Text: formatFinding(f), Detail: f.Detail})
+	}
+
+	for _, t := range section.Tables {
+		table := htmlTable{Title: t.Title}
+		for _, column := range t.Columns {
+			table.Columns = append(table.Columns, htmlColumn{Name: column.Title, Numeric: isNumericColumn(column.Type)})
+		}
+		for _, row := range t.Rows {
+			cells := make([]htmlCell, len(t.Columns))
+			for i, text := range formatSectionRow(t, row) {
+				cells[i].Text = text
+				if i < len(row) && table.Columns[i].Numeric {
+					if v, ok := sectionFloat(row[i]); ok {
+						cells[i].Sort = strconv.FormatFloat(v, 'f', -1, 64)
+					}
+				}
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		hs.Tables = append(hs.Tables, table)
+	}
+	return hs
+}
+
+func numericCell(v float64, format string) htmlCell {
+	return htmlCell{Text: fmt.Sprintf(format, v), Sort: strconv.FormatFloat(v, 'f', -1, 64)}
+}
+
+// reportCurrency returns the currency shared by the cost entries, or "" if they differ
+func reportCurrency(costs []providers.CostData) string {
+	currency := ""
+	for _, cost := range costs {
+		if currency == "" {
+			currency = cost.Currency
+		} else if cost.Currency != currency {
+			return ""
+		}
+	}
+	return currency
+}
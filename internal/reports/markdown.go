@@ -0,0 +1,268 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// OutputOptions holds renderer settings that are not part of the report data
+type OutputOptions struct {
+	// CollapseDetails wraps detail tables in <details> blocks (Markdown output)
+	CollapseDetails bool
+
+	// Template renders the report through a template file or built-in template
+	// instead of the selected format
+	Template string
+}
+
+// OutputMarkdown renders the report as GitHub-flavored Markdown. Headings always appear
+// in the same order: usage, account cost summary, daily breakdown, then report sections
+// sorted by their Order, then by title.
+func (r *Report) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w, collapse: r.Options.CollapseDetails}
+
+	md.printf("# %s Report for %s\n\n", titleCase(r.ReportType), r.ProviderName)
+	md.printf("**Period:** %s to %s  \n", r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02"))
+	md.printf("**Usage data entries:** %d  \n", len(r.UsageData))
+	md.printf("**Cost data entries:** %d\n\n", len(r.CostData))
+
+	if len(r.UsageData) > 0 && (r.ReportType == "usage" || r.ReportType == "full") {
+		usage := make([]providers.UsageData, len(r.UsageData))
+		copy(usage, r.UsageData)
+		sort.SliceStable(usage, func(i, j int) bool {
+			if !usage[i].Timestamp.Equal(usage[j].Timestamp) {
+				return usage[i].Timestamp.Before(usage[j].Timestamp)
+			}
+			if usage[i].Service != usage[j].Service {
+				return usage[i].Service < usage[j].Service
+			}
+			return usage[i].Metric < usage[j].Metric
+		})
+
+		rows := make([][]string, 0, len(usage))
+		for _, u := range usage {
+			rows = append(rows, []string{
+				u.Service,
+				u.Metric,
+				fmt.Sprintf("%.4f", u.Value),
+				u.Unit,
+				u.Timestamp.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		md.printf("## Usage\n\n")
+		md.detailTable(fmt.Sprintf("%d usage entries", len(rows)),
+			[]string{"Service", "Metric", "Value", "Unit", "Timestamp"}, rows, "lllll")
+	}
+
+	if len(r.CostData) > 0 && (r.ReportType == "cost" || r.ReportType == "full") {
+		accountGroups := make(map[string][]providers.CostData)
+		for _, cost := range r.CostData {
+			accountGroups[cost.AccountID] = append(accountGroups[cost.AccountID], cost)
+		}
+		accountIDs := make([]string, 0, len(accountGroups))
+		for accountID := range accountGroups {
+			accountIDs = append(accountIDs, accountID)
+		}
+		sort.Strings(accountIDs)
+
+		// Account cost summary
+		var grandTotal float64
+		currency := ""
+		summaryRows := make([][]string, 0, len(accountIDs)+1)
+		for _, accountID := range accountIDs {
+			accountTotal := 0.0
+			for _, cost := range accountGroups[accountID] {
+				accountTotal += cost.Cost
+				currency = cost.Currency
+			}
+			grandTotal += accountTotal
+			summaryRows = append(summaryRows, []string{accountID, fmt.Sprintf("%.4f", accountTotal), currency})
+		}
+		summaryRows = append(summaryRows, []string{"**TOTAL**", fmt.Sprintf("**%.4f**", grandTotal), currency})
+
+		md.printf("## Account Cost Summary\n\n")
+		md.table([]string{"Account ID", "Total Cost", "Currency"}, summaryRows, "lrl")
+
+		// Daily breakdown per account
+		md.printf("## Daily Breakdown\n\n")
+		for _, accountID := range accountIDs {
+			costs := make([]providers.CostData, len(accountGroups[accountID]))
+			copy(costs, accountGroups[accountID])
+			sort.SliceStable(costs, func(i, j int) bool {
+				if !costs[i].StartTime.Equal(costs[j].StartTime) {
+					return costs[i].StartTime.Before(costs[j].StartTime)
+				}
+				return costs[i].Service < costs[j].Service
+			})
+
+			var rows [][]string
+			accountTotal := 0.0
+			accountCurrency := ""
+			dayTotal := 0.0
+			for i, cost := range costs {
+				accountCurrency = cost.Currency
+				day := cost.StartTime.Format("2006-01-02")
+				rows = append(rows, []string{
+					day,
+					cost.Service,
+					fmt.Sprintf("%.4f %s", cost.Cost, cost.Currency),
+					fmt.Sprintf("%.2f %s", cost.Quantity, cost.UsageUnit),
+					cost.Description,
+				})
+				dayTotal += cost.Cost
+				accountTotal += cost.Cost
+
+				// Close the day once the next row belongs to another date
+				if i == len(costs)-1 || costs[i+1].StartTime.Format("2006-01-02") != day {
+					rows = append(rows, []string{day, "**Daily total**", fmt.Sprintf("**%.4f**", dayTotal), "", ""})
+					dayTotal = 0
+				}
+			}
+
+			md.printf("### Account %s\n\n", accountID)
+			md.printf("Account total: **%.4f %s**\n\n", accountTotal, accountCurrency)
+			md.detailTable(fmt.Sprintf("Daily costs for account %s", accountID),
+				[]string{"Date", "Service", "Cost", "Usage", "Description"}, rows, "llrrl")
+		}
+	}
+
+	for _, section := range r.orderedSections() {
+		md.section(section)
+	}
+
+	return md.err
+}
+
+// markdownWriter accumulates the first write error so rendering code stays linear
+type markdownWriter struct {
+	w        io.Writer
+	collapse bool
+	err      error
+}
+
+func (m *markdownWriter) printf(format string, args ...interface{}) {
+	if m.err != nil {
+		return
+	}
+	_, m.err = fmt.Fprintf(m.w, format, args...)
+}
+
+// table writes a GFM table. align holds one of l, r or c per column.
+func (m *markdownWriter) table(header []string, rows [][]string, align string) {
+	m.printf("|")
+	for _, h := range header {
+		m.printf(" %s |", escapeMarkdownCell(h))
+	}
+	m.printf("\n|")
+	for i := range header {
+		switch {
+		case i < len(align) && align[i] == 'r':
+			m.printf(" ---: |")
+		case i < len(align) && align[i] == 'c':
+			m.printf(" :---: |")
+		default:
+			m.printf(" --- |")
+		}
+	}
+	m.printf("\n")
+
+	for _, row := range rows {
+		m.printf("|")
+		for i := range header {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			m.printf(" %s |", escapeMarkdownCell(cell))
+		}
+		m.printf("\n")
+	}
+	m.printf("\n")
+}
+
+// detailTable writes a table that is collapsed into a <details> block when requested
+func (m *markdownWriter) detailTable(summary string, header []string, rows [][]string, align string) {
+	if m.collapse {
+		m.printf("<details>\n<summary>%s</summary>\n\n", summary)
+	}
+	m.table(header, rows, align)
+	if m.collapse {
+		m.printf("</details>\n\n")
+	}
+}
+
+// section writes a provider section: summary fields as a list, findings, then tables
+func (m *markdownWriter) section(section providers.Section) {
+	m.printf("## %s\n\n", section.Title)
+
+	for _, field := range section.Summary {
+		m.printf("- **%s:** %s\n", escapeMarkdownText(field.Label),
+			escapeMarkdownText(formatSectionValue(field.Value, field.Type, field.Unit)))
+	}
+	if len(section.Summary) > 0 {
+		m.printf("\n")
+	}
+
+	for _, f := range section.Findings {
+		m.printf("> **%s:** %s", strings.ToUpper(f.Severity), escapeMarkdownText(f.Message))
+		if f.Impact > 0 {
+			m.printf(" (%s/month at stake)", formatCurrency(f.Impact, f.Currency))
+		}
+		m.printf("  \n")
+		if f.Detail != "" {
+			m.printf("> %s  \n", escapeMarkdownText(f.Detail))
+		}
+		m.printf("\n")
+	}
+
+	for _, t := range section.Tables {
+		header := make([]string, len(t.Columns))
+		align := make([]byte, len(t.Columns))
+		for i, column := range t.Columns {
+			header[i] = column.Title
+			align[i] = 'l'
+			if isNumericColumn(column.Type) {
+				align[i] = 'r'
+			}
+		}
+		rows := make([][]string, len(t.Rows))
+		for i, row := range t.Rows {
+			rows[i] = formatSectionRow(t, row)
+		}
+
+		m.printf("### %s\n\n", t.Title)
+		m.detailTable(fmt.Sprintf("%s (%d rows)", t.Title, len(rows)), header, rows, string(align))
+	}
+
+	for _, note := range section.Notes {
+		m.printf("%s  \n", escapeMarkdownText(note))
+	}
+	if len(section.Notes) > 0 {
+		m.printf("\n")
+	}
+}
+
+// escapeMarkdownCell makes a value safe to place inside a table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// escapeMarkdownText escapes characters that would otherwise start Markdown syntax
+func escapeMarkdownText(s string) string {
+	replacer := strings.NewReplacer("*", "\\*", "_", "\\_", "`", "\\`", "<", "&lt;")
+	return replacer.Replace(s)
+}
+
+// titleCase upper-cases the first letter of s
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,212 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricFamily is one OpenMetrics gauge and its samples
+type metricFamily struct {
+	name    string
+	help    string
+	unit    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string
+	value  float64
+}
+
+// OutputOpenMetrics writes the report in the OpenMetrics text format. Every metric is
+// a gauge holding the value for the report period:
+//
+//	observability_cost_total{provider,service,account,currency}  cost of the period
+//	observability_usage{provider,service,metric,unit}            usage of the period
+//	observability_license_used{provider,license_type}            licenses in use
+//	observability_license_available{provider,license_type}       licenses purchased
+//	observability_license_utilization_ratio{provider,license_type}
+//	observability_report_start_timestamp_seconds{provider}
+//	observability_report_end_timestamp_seconds{provider}
+//	observability_section_summary{provider,section,field,unit}   numeric section summary fields
+//	observability_section_findings{provider,section,severity}    number of section findings
+func (r *Report) OutputOpenMetrics(w io.Writer) error {
+	for _, family := range r.metricFamilies() {
+		if len(family.samples) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "# TYPE %s gauge\n", family.name)
+		if family.unit != "" {
+			fmt.Fprintf(w, "# UNIT %s %s\n", family.name, family.unit)
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", family.name, family.help)
+
+		for _, sample := range family.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", family.name, formatMetricLabels(sample.labels), formatMetricValue(sample.value)); err != nil {
+				return fmt.Errorf("error writing OpenMetrics output: %w", err)
+			}
+		}
+	}
+
+	_, err := fmt.Fprint(w, "# EOF\n")
+	return err
+}
+
+// OutputOpenMetricsTextfile writes the metrics for the node_exporter textfile collector
+// to <dir>/observability_cost_center_<provider>.prom. The file is written to a temporary
+// name and renamed, so the collector never reads a partial file. It returns the path written.
+func (r *Report) OutputOpenMetricsTextfile(dir string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("observability_cost_center_%s.prom", r.providerKey()))
+
+	tmp, err := os.CreateTemp(dir, ".observability_cost_center_*.prom.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create textfile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := r.OutputOpenMetrics(tmp); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to set textfile permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write textfile: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to move textfile into place: %w", err)
+	}
+	return path, nil
+}
+
+// metricFamilies aggregates the report data into gauges with sorted, stable samples
+func (r *Report) metricFamilies() []metricFamily {
+	type costKey struct{ service, account, currency string }
+	costs := make(map[costKey]float64)
+	for _, c := range r.CostData {
+		costs[costKey{c.Service, c.AccountID, c.Currency}] += c.Cost
+	}
+
+	type usageKey struct{ service, metric, unit string }
+	usage := make(map[usageKey]float64)
+
+	cost := metricFamily{
+		name: "observability_cost_total",
+		help: "Cost over the report period.",
+	}
+	for key, value := range costs {
+		cost.samples = append(cost.samples, metricSample{
+			labels: [][2]string{{"provider", r.providerKey()}, {"service", key.service}, {"account", key.account}, {"currency", key.currency}},
+			value:  value,
+		})
+	}
+
+	licenseUsed := metricFamily{name: "observability_license_used", help: "Licenses in use."}
+	licenseAvailable := metricFamily{name: "observability_license_available", help: "Licenses available, used or not."}
+	licenseUtilization := metricFamily{name: "observability_license_utilization_ratio", help: "Share of available licenses in use (0-1)."}
+
+	for _, u := range r.UsageData {
+		if u.Service == "Licenses" {
+			labels := [][2]string{{"provider", r.providerKey()}, {"license_type", strings.TrimSuffix(u.Metric, " Licenses")}}
+			licenseUsed.samples = append(licenseUsed.samples, metricSample{labels: labels, value: u.Value})
+			licenseAvailable.samples = append(licenseAvailable.samples, metricSample{labels: labels, value: metadataFloat(u.Metadata, "totalLicenses")})
+			licenseUtilization.samples = append(licenseUtilization.samples, metricSample{labels: labels, value: metadataFloat(u.Metadata, "utilizationPct") / 100})
+			continue
+		}
+		usage[usageKey{u.Service, u.Metric, u.Unit}] += u.Value
+	}
+
+	usageFamily := metricFamily{
+		name: "observability_usage",
+		help: "Usage over the report period.",
+	}
+	for key, value := range usage {
+		usageFamily.samples = append(usageFamily.samples, metricSample{
+			labels: [][2]string{{"provider", r.providerKey()}, {"service", key.service}, {"metric", key.metric}, {"unit", key.unit}},
+			value:  value,
+		})
+	}
+
+	sectionSummary := metricFamily{name: "observability_section_summary", help: "Numeric summary fields of report sections."}
+	sectionFindings := metricFamily{name: "observability_section_findings", help: "Findings of report sections by severity."}
+	for _, section := range r.orderedSections() {
+		for _, field := range section.Summary {
+			value, ok := sectionFloat(field.Value)
+			if !ok {
+				continue
+			}
+			sectionSummary.samples = append(sectionSummary.samples, metricSample{
+				labels: [][2]string{{"provider", r.providerKey()}, {"section", section.ID}, {"field", field.Label}, {"unit", field.Unit}},
+				value:  value,
+			})
+		}
+
+		findings := make(map[string]float64)
+		for _, f := range section.Findings {
+			findings[f.Severity]++
+		}
+		for severity, count := range findings {
+			sectionFindings.samples = append(sectionFindings.samples, metricSample{
+				labels: [][2]string{{"provider", r.providerKey()}, {"section", section.ID}, {"severity", severity}},
+				value:  count,
+			})
+		}
+	}
+
+	provider := [][2]string{{"provider", r.providerKey()}}
+	families := []metricFamily{
+		cost,
+		usageFamily,
+		licenseUsed,
+		licenseAvailable,
+		licenseUtilization,
+		{
+			name:    "observability_report_start_timestamp_seconds",
+			help:    "Start of the report period.",
+			unit:    "seconds",
+			samples: []metricSample{{labels: provider, value: float64(r.StartDate.Unix())}},
+		},
+		{
+			name:    "observability_report_end_timestamp_seconds",
+			help:    "End of the report period.",
+			unit:    "seconds",
+			samples: []metricSample{{labels: provider, value: float64(r.EndDate.Unix())}},
+		},
+		sectionSummary,
+		sectionFindings,
+	}
+
+	for _, family := range families {
+		sort.SliceStable(family.samples, func(i, j int) bool {
+			return formatMetricLabels(family.samples[i].labels) < formatMetricLabels(family.samples[j].labels)
+		})
+	}
+	return families
+}
+
+// formatMetricLabels renders {name="value",...}, escaping values per the exposition format
+func formatMetricLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, label[0], escaper.Replace(label[1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,296 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Parquet datasets written by OutputParquet
+const (
+	ParquetDatasetUsage    = "usage"
+	ParquetDatasetCost     = "cost"
+	ParquetDatasetSections = "sections"
+)
+
+// UsageRecord is the Parquet schema of the usage dataset. Columns must only ever be
+// appended so lake tables keep reading older files.
+type UsageRecord struct {
+	Provider  string    `parquet:"provider,dict"`
+	Month     string    `parquet:"month,dict"` // YYYY-MM of Timestamp, also the partition value
+	Service   string    `parquet:"service,dict"`
+	Metric    string    `parquet:"metric"`
+	Value     float64   `parquet:"value"`
+	Unit      string    `parquet:"unit,dict"`
+	Timestamp time.Time `parquet:"timestamp,timestamp(millisecond)"` // UTC
+	AccountID string    `parquet:"account_id,dict"`
+	Metadata  string    `parquet:"metadata"` // provider metadata as a JSON object, "" if none
+}
+
+// CostRecord is the Parquet schema of the cost dataset. Columns must only ever be
+// appended so lake tables keep reading older files.
+type CostRecord struct {
+	Provider    string  `parquet:"provider,dict"`
+	Month       string  `parquet:"month,dict"` // YYYY-MM of StartDate, also the partition value
+	AccountID   string  `parquet:"account_id,dict"`
+	Service     string  `parquet:"service,dict"`
+	ItemName    string  `parquet:"item_name"`
+	StartDate   int32   `parquet:"start_date,date"` // days since the Unix epoch
+	EndDate     int32   `parquet:"end_date,date"`   // days since the Unix epoch
+	Period      string  `parquet:"period,dict"`
+	Cost        float64 `parquet:"cost"`
+	Currency    string  `parquet:"currency,dict"`
+	Quantity    float64 `parquet:"quantity"`
+	UsageUnit   string  `parquet:"usage_unit,dict"`
+	Region      string  `parquet:"region,dict"`
+	Description string  `parquet:"description"`
+	Estimated   bool    `parquet:"estimated"`
+}
+
+// SectionRecord is the Parquet schema of the sections dataset: report sections in
+// long format, one value per row. Columns must only ever be appended.
+type SectionRecord struct {
+	Provider    string   `parquet:"provider,dict"`
+	Month       string   `parquet:"month,dict"` // YYYY-MM of the report start, also the partition value
+	Section     string   `parquet:"section,dict"`
+	Kind        string   `parquet:"kind,dict"` // summary, finding or table
+	Table       string   `parquet:"table,dict"`
+	Row         int32    `parquet:"row"`
+	Column      string   `parquet:"column,dict"`
+	Type        string   `parquet:"type,dict"`
+	Unit        string   `parquet:"unit,dict"`
+	ValueString string   `parquet:"value_string"`          // every value, formatted as in CSV output
+	ValueNumber *float64 `parquet:"value_number,optional"` // set for numeric values
+}
+
+// OutputParquet writes the usage and cost data as Snappy-compressed Parquet files.
+//
+// If path is a directory (it exists, or ends with a path separator) the files are
+// partitioned Hive-style by provider and month:
+//
+//	<dir>/usage/provider=<provider>/month=<YYYY-MM>/part-<start>-<end>.parquet
+//	<dir>/cost/provider=<provider>/month=<YYYY-MM>/part-<start>-<end>.parquet
+//	<dir>/sections/provider=<provider>/month=<YYYY-MM>/part-<start>-<end>.parquet
+//
+// where start and end are the report period, so re-running a report replaces its own
+// files. Otherwise report.parquet becomes report-usage.parquet and report-cost.parquet,
+// plus report-sections.parquet when the report has sections. It returns the paths
+// written.
+func (r *Report) OutputParquet(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("parquet output needs a file or directory path (--output-file)")
+	}
+
+	usage, cost, err := r.parquetRecords()
+	if err != nil {
+		return nil, err
+	}
+	sections := r.parquetSectionRecords()
+
+	if isDirectoryPath(path) {
+		return r.outputParquetPartitions(path, usage, cost, sections)
+	}
+
+	ext := filepath.Ext(path)
+	if ext == "" {
+		ext = ".parquet"
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+
+	usagePath := fmt.Sprintf("%s-%s%s", base, ParquetDatasetUsage, ext)
+	if err := writeParquetFile(usagePath, usage); err != nil {
+		return nil, err
+	}
+	costPath := fmt.Sprintf("%s-%s%s", base, ParquetDatasetCost, ext)
+	if err := writeParquetFile(costPath, cost); err != nil {
+		return nil, err
+	}
+
+	written := []string{usagePath, costPath}
+	if len(sections) > 0 {
+		sectionsPath := fmt.Sprintf("%s-%s%s", base, ParquetDatasetSections, ext)
+		if err := writeParquetFile(sectionsPath, sections); err != nil {
+			return written, err
+		}
+		written = append(written, sectionsPath)
+	}
+
+	return written, nil
+}
+
+// outputParquetPartitions writes one file per dataset, provider and month
+func (r *Report) outputParquetPartitions(dir string, usage []UsageRecord, cost []CostRecord, sections []SectionRecord) ([]string, error) {
+	usageByMonth := make(map[string][]UsageRecord)
+	for _, record := range usage {
+		usageByMonth[record.Month] = append(usageByMonth[record.Month], record)
+	}
+	costByMonth := make(map[string][]CostRecord)
+	for _, record := range cost {
+		costByMonth[record.Month] = append(costByMonth[record.Month], record)
+	}
+
+	fileName := fmt.Sprintf("part-%s-%s.parquet", r.StartDate.Format("20060102"), r.EndDate.Format("20060102"))
+	partition := func(dataset, month string) string {
+		return filepath.Join(dir, dataset, "provider="+r.providerKey(), "month="+month, fileName)
+	}
+
+	var written []string
+	for _, month := range sortedMonths(usageByMonth) {
+		path := partition(ParquetDatasetUsage, month)
+		if err := writeParquetFile(path, usageByMonth[month]); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	for _, month := range sortedMonths(costByMonth) {
+		path := partition(ParquetDatasetCost, month)
+		if err := writeParquetFile(path, costByMonth[month]); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	if len(sections) > 0 {
+		path := partition(ParquetDatasetSections, sections[0].Month)
+		if err := writeParquetFile(path, sections); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// parquetRecords converts the report data to Parquet rows
+func (r *Report) parquetRecords() ([]UsageRecord, []CostRecord, error) {
+	usage := make([]UsageRecord, 0, len(r.UsageData))
+	for _, u := range r.UsageData {
+		metadata := ""
+		if len(u.Metadata) > 0 {
+			data, err := json.Marshal(u.Metadata)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error encoding usage metadata: %w", err)
+			}
+			metadata = string(data)
+		}
+
+		timestamp := u.Timestamp.UTC()
+		usage = append(usage, UsageRecord{
+			Provider:  r.providerKey(),
+			Month:     timestamp.Format("2006-01"),
+			Service:   u.Service,
+			Metric:    u.Metric,
+			Value:     u.Value,
+			Unit:      u.Unit,
+			Timestamp: timestamp,
+			AccountID: metadataString(u.Metadata, "accountId"),
+			Metadata:  metadata,
+		})
+	}
+
+	cost := make([]CostRecord, 0, len(r.CostData))
+	for _, c := range r.CostData {
+		cost = append(cost, CostRecord{
+			Provider:    r.providerKey(),
+			Month:       c.StartTime.UTC().Format("2006-01"),
+			AccountID:   c.AccountID,
+			Service:     c.Service,
+			ItemName:    c.ItemName,
+			StartDate:   epochDays(c.StartTime),
+			EndDate:     epochDays(c.EndTime),
+			Period:      c.Period,
+			Cost:        c.Cost,
+			Currency:    c.Currency,
+			Quantity:    c.Quantity,
+			UsageUnit:   c.UsageUnit,
+			Region:      c.Region,
+			Description: c.Description,
+			Estimated:   c.Estimated,
+		})
+	}
+
+	return usage, cost, nil
+}
+
+// parquetSectionRecords flattens the report sections. Sections describe the whole
+// report period, so they are filed under the month the period starts in.
+func (r *Report) parquetSectionRecords() []SectionRecord {
+	var records []SectionRecord
+	for _, section := range r.orderedSections() {
+		for _, cell := range sectionCells(section) {
+			record := SectionRecord{
+				Provider:    r.providerKey(),
+				Month:       r.StartDate.Format("2006-01"),
+				Section:     cell.Section,
+				Kind:        cell.Kind,
+				Table:       cell.Table,
+				Row:         int32(cell.Row),
+				Column:      cell.Column,
+				Type:        string(cell.Type),
+				Unit:        cell.Unit,
+				ValueString: rawSectionValue(cell.Value, cell.Type),
+			}
+			if v, ok := sectionFloat(cell.Value); ok {
+				record.ValueNumber = &v
+			}
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// writeParquetFile writes rows to path, creating parent directories as needed
+func writeParquetFile[T any](path string, rows []T) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	writer := parquet.NewGenericWriter[T](file, parquet.Compression(&parquet.Snappy))
+	if _, err := writer.Write(rows); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := writer.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return file.Close()
+}
+
+// isDirectoryPath reports whether path names a directory rather than a file
+func isDirectoryPath(path string) bool {
+	if strings.HasSuffix(path, string(os.PathSeparator)) || strings.HasSuffix(path, "/") {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// epochDays converts a time to the Parquet DATE representation
+func epochDays(t time.Time) int32 {
+	if t.IsZero() {
+		return 0
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int32(day.Unix() / int64(24*time.Hour/time.Second))
+}
+
+func sortedMonths[T any](byMonth map[string][]T) []string {
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months
+}
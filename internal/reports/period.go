@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a report date range. End is exclusive; providers take the
+// inclusive LastDay instead.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Label string
+}
+
+var rollingPeriodPattern = regexp.MustCompile(`^(?:last-)?(\d+)([dw])$`)
+
+// ParsePeriod parses a period spec relative to now:
+//
+//	2026-09                 the calendar month
+//	2026-09-01..2026-09-15  an explicit range, end exclusive
+//	last-30d, 30d, last-4w  a rolling window ending today (exclusive)
+func ParsePeriod(spec string, now time.Time) (Period, error) {
+	spec = strings.TrimSpace(spec)
+
+	if month, err := time.Parse("2006-01", spec); err == nil {
+		return Period{Start: month, End: month.AddDate(0, 1, 0), Label: spec}, nil
+	}
+
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		start, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return Period{}, fmt.Errorf("error parsing period start %q: %w", from, err)
+		}
+		end, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return Period{}, fmt.Errorf("error parsing period end %q: %w", to, err)
+		}
+		if !end.After(start) {
+			return Period{}, fmt.Errorf("period %s ends before it starts", spec)
+		}
+		return Period{Start: start, End: end, Label: spec}, nil
+	}
+
+	if m := rollingPeriodPattern.FindStringSubmatch(spec); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n == 0 {
+			return Period{}, fmt.Errorf("period %s is empty", spec)
+		}
+		if m[2] == "w" {
+			n *= 7
+		}
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return Period{Start: end.AddDate(0, 0, -n), End: end, Label: spec}, nil
+	}
+
+	return Period{}, fmt.Errorf("unsupported period %q (use YYYY-MM, YYYY-MM-DD..YYYY-MM-DD or last-<N>d)", spec)
+}
+
+// Previous returns the period right before p: the previous calendar month for a
+// month, otherwise a window of the same length
+func (p Period) Previous() Period {
+	if p.Start.Day() == 1 && p.End.Equal(p.Start.AddDate(0, 1, 0)) {
+		start := p.Start.AddDate(0, -1, 0)
+		return Period{Start: start, End: p.Start, Label: start.Format("2006-01")}
+	}
+
+	length := p.End.Sub(p.Start)
+	start := p.Start.Add(-length)
+	return Period{Start: start, End: p.Start, Label: fmt.Sprintf("%s..%s", start.Format("2006-01-02"), p.Start.Format("2006-01-02"))}
+}
+
+// Days returns the number of days in the period
+func (p Period) Days() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
+}
+
+// String formats the period as its label, or its date range
+func (p Period) String() string {
+	if p.Label != "" {
+		return p.Label
+	}
+	return fmt.Sprintf("%s..%s", p.Start.Format("2006-01-02"), p.End.Format("2006-01-02"))
+}
+
+// LastDay returns the last day of the period, the inclusive end date providers
+// expect.
+func (p Period) LastDay() time.Time {
+	return p.End.AddDate(0, 0, -1)
+}
@@ -0,0 +1,106 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ilhicas/observability-cost-center/internal/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PriceList is the pricing catalog used to estimate cost from usage
+type PriceList struct {
+	Catalog *pricing.Catalog
+}
+
+// Output writes the prices in the given format (table, json, markdown or csv) to
+// stdout, or to filePath when it is set. The json and csv output can be edited and
+// imported back as pricing.catalog.
+func (l *PriceList) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return l.OutputTable(writer)
+	case "json":
+		return WriteJSON(writer, l.Catalog)
+	case "markdown", "md":
+		return l.OutputMarkdown(writer)
+	case "csv":
+		return l.OutputCSV(writer)
+	default:
+		return fmt.Errorf("unsupported output format for pricing: %s (use table, json, markdown or csv)", format)
+	}
+}
+
+var priceListColumns = []string{"Provider", "Region", "Option", "Service", "Metric", "Price", "Per", "Cost Item"}
+
+// rows formats one row per price; empty regions and options apply to all
+func (l *PriceList) rows() [][]string {
+	orAll := func(s string) string {
+		if s == "" {
+			return "all"
+		}
+		return s
+	}
+
+	rows := make([][]string, 0, len(l.Catalog.Prices))
+	for _, p := range l.Catalog.Prices {
+		per := p.Unit
+		if p.Monthly {
+			per += "-month"
+		}
+		item := p.Item
+		if p.CostService != "" {
+			item = p.CostService + " / " + item
+		}
+		rows = append(rows, []string{
+			p.Provider, orAll(p.Region), orAll(p.Option), p.Service, p.Metric,
+			fmt.Sprintf("%s %s", strconv.FormatFloat(p.Price, 'f', -1, 64), p.Currency), per, item,
+		})
+	}
+	return rows
+}
+
+// OutputTable writes the prices as a plain-text table
+func (l *PriceList) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "Pricing catalog %s (%d prices)\n\n", l.Catalog.Version, len(l.Catalog.Prices))
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(priceListColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+	})
+	table.AppendBulk(l.rows())
+	table.Render()
+	return nil
+}
+
+// OutputMarkdown writes the prices as GitHub-flavored Markdown
+func (l *PriceList) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+	md.printf("# Pricing catalog %s\n\n", l.Catalog.Version)
+	md.table(priceListColumns, l.rows(), "lllllrll")
+	return md.err
+}
+
+// OutputCSV writes the prices in the CSV format pricing.catalog imports
+func (l *PriceList) OutputCSV(w io.Writer) error {
+	records := [][]string{pricing.CSVColumns}
+	for _, p := range l.Catalog.Prices {
+		records = append(records, p.CSVRecord())
+	}
+	return writeCSVRecords(w, records)
+}
@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/recommend"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Recommendations lists savings recommendations, largest savings first
+type Recommendations struct {
+	Provider        string
+	Recommendations []recommend.Recommendation
+}
+
+// Output writes the recommendations in the given format (table, json, markdown or csv)
+// to stdout, or to filePath when it is set
+func (r *Recommendations) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return r.OutputTable(writer)
+	case "json":
+		return r.OutputJSON(writer)
+	case "markdown", "md":
+		return r.OutputMarkdown(writer)
+	case "csv":
+		return r.OutputCSV(writer)
+	default:
+		return fmt.Errorf("unsupported output format for recommendations: %s (use table, json, markdown or csv)", format)
+	}
+}
+
+var recommendationColumns = []string{"#", "Rule", "Resource", "Action", "Savings/Month", "Confidence", "Evidence"}
+
+func (r *Recommendations) rows() [][]string {
+	rows := make([][]string, 0, len(r.Recommendations))
+	for i, rec := range r.Recommendations {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1), rec.Rule, rec.Resource, rec.Action,
+			formatCurrency(rec.MonthlySavings, rec.Currency), rec.Confidence, strings.Join(rec.Evidence, "; "),
+		})
+	}
+	return rows
+}
+
+func (r *Recommendations) summary() string {
+	if len(r.Recommendations) == 0 {
+		return fmt.Sprintf("No savings recommendations for %s", r.Provider)
+	}
+	return fmt.Sprintf("%d recommendations for %s, saving up to %s per month",
+		len(r.Recommendations), r.Provider, formatCurrency(recommend.TotalSavings(r.Recommendations), r.Recommendations[0].Currency))
+}
+
+// OutputTable writes the recommendations as a plain-text table
+func (r *Recommendations) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s\n\n", r.summary())
+	if len(r.Recommendations) == 0 {
+		return nil
+	}
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(recommendationColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+	})
+	table.AppendBulk(r.rows())
+	table.Render()
+
+	return nil
+}
+
+// OutputMarkdown writes the recommendations as GitHub-flavored Markdown
+func (r *Recommendations) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Savings recommendations\n\n")
+	md.printf("%s.\n\n", r.summary())
+	if len(r.Recommendations) > 0 {
+		md.table(recommendationColumns, r.rows(), "rlllrll")
+	}
+
+	return md.err
+}
+
+// OutputJSON writes the recommendations as JSON
+func (r *Recommendations) OutputJSON(w io.Writer) error {
+	output := struct {
+		Provider        string                     `json:"provider"`
+		TotalSavings    float64                    `json:"totalMonthlySavings"`
+		Recommendations []recommend.Recommendation `json:"recommendations"`
+	}{
+		Provider:        r.Provider,
+		TotalSavings:    recommend.TotalSavings(r.Recommendations),
+		Recommendations: append([]recommend.Recommendation{}, r.Recommendations...),
+	}
+
+	return WriteJSON(w, output)
+}
+
+// recommendationCSVColumns is the column order of the CSV export, one row per recommendation
+var recommendationCSVColumns = []string{
+	"id", "rule", "provider", "account_id", "resource", "action", "monthly_savings", "currency", "confidence", "evidence",
+}
+
+// OutputCSV writes the recommendations as CSV, for ticketing or spreadsheet imports
+func (r *Recommendations) OutputCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(recommendationCSVColumns); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+	for _, rec := range r.Recommendations {
+		record := []string{
+			rec.ID, rec.Rule, rec.Provider, rec.AccountID, rec.Resource, rec.Action,
+			formatCSVFloat(rec.MonthlySavings), rec.Currency, rec.Confidence, strings.Join(rec.Evidence, "; "),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
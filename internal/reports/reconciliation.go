@@ -0,0 +1,274 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/reconcile"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Reconciliation compares the cost expected from usage and the pricing catalog with
+// the billed cost, per service and day
+type Reconciliation struct {
+	Provider  string
+	StartDate time.Time
+	EndDate   time.Time // Exclusive
+	Options   reconcile.Options
+	Lines     []reconcile.Line
+
+	// All lists every line in table and markdown output, not only discrepancies
+	All bool
+}
+
+// Output writes the reconciliation in the given format (table, json, markdown or csv)
+// to stdout, or to filePath when it is set
+func (r *Reconciliation) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return r.OutputTable(writer)
+	case "json":
+		return r.OutputJSON(writer)
+	case "markdown", "md":
+		return r.OutputMarkdown(writer)
+	case "csv":
+		return r.OutputCSV(writer)
+	default:
+		return fmt.Errorf("unsupported output format for reconciliation: %s (use table, json, markdown or csv)", format)
+	}
+}
+
+var reconciliationColumns = []string{"Date", "Period", "Service", "Expected", "Billed", "Delta", "Delta %", "Status"}
+
+// statusNotes explain the discrepancy statuses
+var statusNotes = map[string]string{
+	reconcile.StatusOverBilled:   "billed above usage at catalog prices: a price increase, or usage that is not collected",
+	reconcile.StatusUnderBilled:  "billed below usage at catalog prices: a discount, credit or free tier",
+	reconcile.StatusNotEstimated: "billed without usage priced in the catalog: a metric that is not collected or has no price",
+	reconcile.StatusNotBilled:    "usage priced in the catalog without billed cost: a free tier, or billing under another service",
+}
+
+// shown returns the lines listed in table and markdown output
+func (r *Reconciliation) shown() []reconcile.Line {
+	if r.All {
+		return r.Lines
+	}
+	var lines []reconcile.Line
+	for _, l := range r.Lines {
+		if l.Discrepancy() {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func (r *Reconciliation) rows() [][]string {
+	lines := r.shown()
+	rows := make([][]string, 0, len(lines))
+	for _, l := range lines {
+		rows = append(rows, []string{
+			l.Date.Format("2006-01-02"),
+			l.Period,
+			l.Service,
+			formatCurrency(l.Expected, l.Currency),
+			formatCurrency(l.Billed, l.Currency),
+			formatSignedCurrency(l.Delta(), l.Currency),
+			formatDeltaPct(percentChange(l.Expected, l.Billed)),
+			l.Status,
+		})
+	}
+	return rows
+}
+
+// currency returns the currency of the lines
+func (r *Reconciliation) currency() string {
+	for _, l := range r.Lines {
+		if l.Currency != "" {
+			return l.Currency
+		}
+	}
+	return "USD"
+}
+
+// headline summarizes the totals and the number of discrepancies
+func (r *Reconciliation) headline() []string {
+	s := reconcile.Summarize(r.Lines)
+	currency := r.currency()
+	return []string{
+		fmt.Sprintf("Expected %s, billed %s (%s, %s)",
+			formatCurrency(s.Expected, currency), formatCurrency(s.Billed, currency),
+			formatSignedCurrency(s.Billed-s.Expected, currency), formatDeltaPct(percentChange(s.Expected, s.Billed))),
+		fmt.Sprintf("%d of %d lines differ by more than %s and %s",
+			s.Discrepancies, s.Lines, formatPercent(r.Options.Tolerance), formatCurrency(r.Options.MinDelta, currency)),
+	}
+}
+
+// notes explains the statuses of the lines shown
+func (r *Reconciliation) notes() []string {
+	seen := make(map[string]bool)
+	var notes []string
+	for _, status := range []string{reconcile.StatusOverBilled, reconcile.StatusUnderBilled, reconcile.StatusNotEstimated, reconcile.StatusNotBilled} {
+		for _, l := range r.shown() {
+			if l.Status == status && !seen[status] {
+				seen[status] = true
+				notes = append(notes, fmt.Sprintf("%s: %s", status, statusNotes[status]))
+			}
+		}
+	}
+	return notes
+}
+
+func (r *Reconciliation) title() string {
+	return fmt.Sprintf("Reconciliation of %s cost from %s to %s", r.Provider,
+		r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02"))
+}
+
+// OutputTable writes the totals and the lines outside the tolerance as a plain-text table
+func (r *Reconciliation) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s\n", r.title())
+	for _, line := range r.headline() {
+		fmt.Fprintf(w, "%s\n", line)
+	}
+	fmt.Fprintln(w)
+
+	rows := r.rows()
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No discrepancies")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(reconciliationColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT,
+	})
+	table.AppendBulk(rows)
+	table.Render()
+
+	if notes := r.notes(); len(notes) > 0 {
+		fmt.Fprintf(w, "\nStatuses:\n  - %s\n", strings.Join(notes, "\n  - "))
+	}
+	return nil
+}
+
+// OutputMarkdown writes the reconciliation as GitHub-flavored Markdown
+func (r *Reconciliation) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# %s\n\n", r.title())
+	for _, line := range r.headline() {
+		md.printf("- %s\n", line)
+	}
+	md.printf("\n")
+
+	rows := r.rows()
+	if len(rows) == 0 {
+		md.printf("No discrepancies.\n")
+		return md.err
+	}
+	md.table(reconciliationColumns, rows, "lllrrrrl")
+
+	if notes := r.notes(); len(notes) > 0 {
+		md.printf("## Statuses\n\n")
+		for _, note := range notes {
+			md.printf("- %s\n", note)
+		}
+		md.printf("\n")
+	}
+	return md.err
+}
+
+// OutputJSON writes the totals and every line as JSON
+func (r *Reconciliation) OutputJSON(w io.Writer) error {
+	type jsonLine struct {
+		Date        string   `json:"date"`
+		Period      string   `json:"period"`
+		Service     string   `json:"service"`
+		Expected    float64  `json:"expected"`
+		Billed      float64  `json:"billed"`
+		Delta       float64  `json:"delta"`
+		DeltaPct    *float64 `json:"deltaPct"`
+		Currency    string   `json:"currency"`
+		Status      string   `json:"status"`
+		Discrepancy bool     `json:"discrepancy"`
+	}
+
+	s := reconcile.Summarize(r.Lines)
+	output := struct {
+		Provider      string     `json:"provider"`
+		StartDate     string     `json:"startDate"`
+		EndDate       string     `json:"endDate"`
+		Tolerance     float64    `json:"tolerance"`
+		MinDelta      float64    `json:"minDelta"`
+		Expected      float64    `json:"expected"`
+		Billed        float64    `json:"billed"`
+		Discrepancies int        `json:"discrepancies"`
+		Lines         []jsonLine `json:"lines"`
+	}{
+		Provider:      r.Provider,
+		StartDate:     r.StartDate.Format("2006-01-02"),
+		EndDate:       r.EndDate.Format("2006-01-02"),
+		Tolerance:     r.Options.Tolerance,
+		MinDelta:      r.Options.MinDelta,
+		Expected:      s.Expected,
+		Billed:        s.Billed,
+		Discrepancies: s.Discrepancies,
+		Lines:         []jsonLine{},
+	}
+	for _, l := range r.Lines {
+		output.Lines = append(output.Lines, jsonLine{
+			Date:        l.Date.Format("2006-01-02"),
+			Period:      l.Period,
+			Service:     l.Service,
+			Expected:    l.Expected,
+			Billed:      l.Billed,
+			Delta:       l.Delta(),
+			DeltaPct:    percentChange(l.Expected, l.Billed),
+			Currency:    l.Currency,
+			Status:      l.Status,
+			Discrepancy: l.Discrepancy(),
+		})
+	}
+
+	return WriteJSON(w, output)
+}
+
+// reconciliationCSVColumns is the column order of the CSV export, one row per line
+var reconciliationCSVColumns = []string{
+	"provider", "date", "period", "service", "expected", "billed", "delta", "currency", "status",
+}
+
+// OutputCSV writes every line as CSV
+func (r *Reconciliation) OutputCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reconciliationCSVColumns); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+	for _, l := range r.Lines {
+		record := []string{
+			r.Provider, l.Date.Format("2006-01-02"), l.Period, l.Service,
+			formatCSVFloat(l.Expected), formatCSVFloat(l.Billed), formatCSVFloat(l.Delta()), l.Currency, l.Status,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ilhicas/observability-cost-center/internal/providers"
@@ -14,27 +15,50 @@ import (
 
 // Report represents a generated report with usage and cost data
 type Report struct {
-	ProviderName   string
-	StartDate      time.Time
-	EndDate        time.Time
-	UsageData      []providers.UsageData
-	CostData       []providers.CostData
-	ReportType     string
-	TotalCost      float64
-	CustomSections map[string]string
+	ProviderName string
+	ProviderKey  string // Configured provider key, e.g. aws; see providerKey
+	StartDate    time.Time
+	EndDate      time.Time
+	UsageData    []providers.UsageData
+	CostData     []providers.CostData
+	ReportType   string
+	TotalCost    float64
+	Sections     []providers.Section
+	Options      OutputOptions
+}
+
+// providerKey identifies the provider in partition paths, file names and metric labels:
+// the configured key when it is set, or else the provider name in lower case with every
+// run of other characters than letters and digits replaced by an underscore
+func (r *Report) providerKey() string {
+	if r.ProviderKey != "" {
+		return r.ProviderKey
+	}
+	var b strings.Builder
+	underscore := false
+	for _, c := range strings.ToLower(r.ProviderName) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
 }
 
 func (r *Report) OutputJSON(w io.Writer) error {
 	// Create a structured representation of the report for JSON output
 	type jsonReport struct {
-		Provider       string                 `json:"provider"`
-		ReportType     string                 `json:"reportType"`
-		StartDate      string                 `json:"startDate"`
-		EndDate        string                 `json:"endDate"`
-		UsageData      []providers.UsageData  `json:"usageData,omitempty"`
-		CostData       []providers.CostData   `json:"costData,omitempty"`
-		CustomSections map[string]string      `json:"customSections,omitempty"`
-		Summary        map[string]interface{} `json:"summary"`
+		Provider   string                 `json:"provider"`
+		ReportType string                 `json:"reportType"`
+		StartDate  string                 `json:"startDate"`
+		EndDate    string                 `json:"endDate"`
+		UsageData  []providers.UsageData  `json:"usageData,omitempty"`
+		CostData   []providers.CostData   `json:"costData,omitempty"`
+		Sections   []providers.Section    `json:"sections,omitempty"`
+		Summary    map[string]interface{} `json:"summary"`
 	}
 
 	// Calculate summary information
@@ -79,26 +103,32 @@ func (r *Report) OutputJSON(w io.Writer) error {
 
 	// Create the JSON report
 	report := jsonReport{
-		Provider:       r.ProviderName,
-		ReportType:     r.ReportType,
-		StartDate:      r.StartDate.Format("2006-01-02"),
-		EndDate:        r.EndDate.Format("2006-01-02"),
-		UsageData:      r.UsageData,
-		CostData:       r.CostData,
-		CustomSections: r.CustomSections,
-		Summary:        summary,
+		Provider:   r.ProviderName,
+		ReportType: r.ReportType,
+		StartDate:  r.StartDate.Format("2006-01-02"),
+		EndDate:    r.EndDate.Format("2006-01-02"),
+		UsageData:  r.UsageData,
+		CostData:   r.CostData,
+		Sections:   r.orderedSections(),
+		Summary:    summary,
 	}
 
 	// Marshal the report to JSON
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ") // Pretty print with 2-space indentation
-	if err := encoder.Encode(report); err != nil {
+	if err := WriteJSON(w, report); err != nil {
 		return fmt.Errorf("error encoding report to JSON: %w", err)
 	}
 
 	return nil
 }
 
+// WriteJSON encodes v as indented JSON. Every JSON output of the tool goes through it
+// so that all formats share the same layout.
+func WriteJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ") // Pretty print with 2-space indentation
+	return encoder.Encode(v)
+}
+
 func (r *Report) OutputTable(w io.Writer) error {
 	// Write report header
 	fmt.Fprintf(w, "Report for %s\n", r.ProviderName)
@@ -131,7 +161,7 @@ func (r *Report) OutputTable(w io.Writer) error {
 
 	// Display cost data if available
 	if len(r.CostData) > 0 && (r.ReportType == "cost" || r.ReportType == "full") {
-		fmt.Fprintln(w, "Cost Data:\n")
+		fmt.Fprint(w, "Cost Data:\n\n")
 
 		// Group cost data by account
 		accountGroups := make(map[string][]providers.CostData)
@@ -231,12 +261,9 @@ func (r *Report) OutputTable(w io.Writer) error {
 		}
 	}
 
-	// Display custom sections if any
-	if len(r.CustomSections) > 0 {
-		for title, content := range r.CustomSections {
-			fmt.Fprintf(w, "\n=== %s ===\n\n", title)
-			fmt.Fprintln(w, content)
-		}
+	// Display report sections if any
+	for _, section := range r.orderedSections() {
+		writeSectionText(w, section)
 	}
 
 	return nil
@@ -425,20 +452,6 @@ func (r *Report) outputAsTableV1() error {
 	return nil
 }
 
-// outputAsJSON outputs the report in JSON format
-func (r *Report) outputAsJSON() error {
-	// Implementation would go here
-	fmt.Println("JSON output not yet implemented")
-	return nil
-}
-
-// outputAsCSV outputs the report in CSV format
-func (r *Report) outputAsCSV() error {
-	// Implementation would go here
-	fmt.Println("CSV output not yet implemented")
-	return nil
-}
-
 // Helper function to truncate strings for better report formatting
 func truncateString(s string, maxLength int) string {
 	if len(s) <= maxLength {
@@ -0,0 +1,288 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/olekukonko/tablewriter"
+)
+
+// AddSection adds a structured section to the report. Empty sections are skipped.
+func (r *Report) AddSection(section providers.Section) {
+	if section.IsEmpty() {
+		return
+	}
+	r.Sections = append(r.Sections, section)
+}
+
+// orderedSections returns the sections sorted by Order, then by Title, so every
+// format renders them in the same order on every run
+func (r *Report) orderedSections() []providers.Section {
+	sections := make([]providers.Section, len(r.Sections))
+	copy(sections, r.Sections)
+	sort.SliceStable(sections, func(i, j int) bool {
+		if sections[i].Order != sections[j].Order {
+			return sections[i].Order < sections[j].Order
+		}
+		return sections[i].Title < sections[j].Title
+	})
+	return sections
+}
+
+// OutputSections writes standalone sections, for commands whose result is a section
+// rather than a whole report, as table, json or markdown to stdout, or to filePath
+// when it is set
+func OutputSections(format, filePath string, sections ...providers.Section) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		for _, section := range sections {
+			writeSectionText(writer, section)
+		}
+		return nil
+	case "json":
+		return WriteJSON(writer, sections)
+	case "markdown", "md":
+		md := &markdownWriter{w: writer}
+		for _, section := range sections {
+			md.section(section)
+		}
+		return md.err
+	default:
+		return fmt.Errorf("unsupported output format: %s (use table, json or markdown)", format)
+	}
+}
+
+// formatSectionValue formats a typed section value for display
+func formatSectionValue(v interface{}, typ providers.ColumnType, unit string) string {
+	if v == nil {
+		return ""
+	}
+
+	switch typ {
+	case providers.ColumnCurrency:
+		if f, ok := sectionFloat(v); ok {
+			return formatCurrency(f, unit)
+		}
+	case providers.ColumnPercent:
+		if f, ok := sectionFloat(v); ok {
+			return formatPercent(f)
+		}
+	case providers.ColumnInteger:
+		if f, ok := sectionFloat(v); ok {
+			return formatThousands(f, 0)
+		}
+	case providers.ColumnNumber:
+		if f, ok := sectionFloat(v); ok {
+			return formatThousands(f, 2)
+		}
+	case providers.ColumnDate:
+		if t, ok := v.(time.Time); ok {
+			return t.Format("2006-01-02")
+		}
+	case providers.ColumnDateTime:
+		if t, ok := v.(time.Time); ok {
+			return t.Format("2006-01-02 15:04:05")
+		}
+	}
+
+	return fmt.Sprint(v)
+}
+
+// sectionFloat converts numeric section values to float64
+func sectionFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// isNumericColumn reports whether values of the type are right-aligned numbers
+func isNumericColumn(typ providers.ColumnType) bool {
+	switch typ {
+	case providers.ColumnInteger, providers.ColumnNumber, providers.ColumnCurrency, providers.ColumnPercent:
+		return true
+	}
+	return false
+}
+
+// formatFinding renders a finding as one line, including its impact when known
+func formatFinding(f providers.Finding) string {
+	line := fmt.Sprintf("[%s] %s", strings.ToUpper(f.Severity), f.Message)
+	if f.Impact > 0 {
+		line += fmt.Sprintf(" (%s/month at stake)", formatCurrency(f.Impact, f.Currency))
+	}
+	return line
+}
+
+// writeSectionText renders a section for the plain-text table formats
+func writeSectionText(w io.Writer, section providers.Section) {
+	fmt.Fprintf(w, "\n=== %s ===\n\n", section.Title)
+	writeSectionBody(w, section)
+}
+
+// writeSectionBody renders the summary, findings, tables and notes of a section
+func writeSectionBody(w io.Writer, section providers.Section) {
+	if len(section.Summary) > 0 {
+		for _, field := range section.Summary {
+			fmt.Fprintf(w, "  %s: %s\n", field.Label, formatSectionValue(field.Value, field.Type, field.Unit))
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, f := range section.Findings {
+		fmt.Fprintf(w, "%s\n", formatFinding(f))
+		if f.Detail != "" {
+			fmt.Fprintf(w, "    %s\n", f.Detail)
+		}
+	}
+	if len(section.Findings) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, t := range section.Tables {
+		fmt.Fprintf(w, "%s:\n", t.Title)
+
+		table := tablewriter.NewWriter(&writerAdapter{w: w})
+		header := make([]string, len(t.Columns))
+		alignments := make([]int, len(t.Columns))
+		for i, column := range t.Columns {
+			header[i] = column.Title
+			alignments[i] = tablewriter.ALIGN_LEFT
+			if isNumericColumn(column.Type) {
+				alignments[i] = tablewriter.ALIGN_RIGHT
+			}
+		}
+		table.SetHeader(header)
+		table.SetAutoFormatHeaders(true)
+		table.SetAutoWrapText(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetColumnAlignment(alignments)
+
+		for _, row := range t.Rows {
+			table.Append(formatSectionRow(t, row))
+		}
+		table.Render()
+		fmt.Fprintln(w)
+	}
+
+	for _, note := range section.Notes {
+		fmt.Fprintln(w, note)
+	}
+}
+
+// formatSectionRow formats every cell of a table row according to its column
+func formatSectionRow(t providers.SectionTable, row []interface{}) []string {
+	cells := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		if i < len(row) {
+			cells[i] = formatSectionValue(row[i], column.Type, column.Unit)
+		}
+	}
+	return cells
+}
+
+// Kinds of sectionCell
+const (
+	sectionKindSummary = "summary"
+	sectionKindFinding = "finding"
+	sectionKindTable   = "table"
+)
+
+// sectionCell is one value of a section in long format, as written to the tabular
+// formats (CSV, Parquet) where sections of any shape must share one schema
+type sectionCell struct {
+	Section string
+	Kind    string
+	Table   string
+	Row     int
+	Column  string
+	Type    providers.ColumnType
+	Unit    string
+	Value   interface{}
+}
+
+// sectionCells flattens a section into cells: summary fields, then findings (one
+// row each), then every table cell
+func sectionCells(section providers.Section) []sectionCell {
+	var cells []sectionCell
+	for i, field := range section.Summary {
+		cells = append(cells, sectionCell{
+			Section: section.ID, Kind: sectionKindSummary, Row: i,
+			Column: field.Label, Type: field.Type, Unit: field.Unit, Value: field.Value,
+		})
+	}
+
+	for i, f := range section.Findings {
+		finding := sectionCell{Section: section.ID, Kind: sectionKindFinding, Row: i}
+		for _, c := range []struct {
+			column string
+			typ    providers.ColumnType
+			unit   string
+			value  interface{}
+		}{
+			{"severity", providers.ColumnString, "", f.Severity},
+			{"message", providers.ColumnString, "", f.Message},
+			{"detail", providers.ColumnString, "", f.Detail},
+			{"impact", providers.ColumnCurrency, f.Currency, f.Impact},
+		} {
+			finding.Column, finding.Type, finding.Unit, finding.Value = c.column, c.typ, c.unit, c.value
+			cells = append(cells, finding)
+		}
+	}
+
+	for _, t := range section.Tables {
+		for i, row := range t.Rows {
+			for j, column := range t.Columns {
+				var value interface{}
+				if j < len(row) {
+					value = row[j]
+				}
+				cells = append(cells, sectionCell{
+					Section: section.ID, Kind: sectionKindTable, Table: t.Title, Row: i,
+					Column: column.Key, Type: column.Type, Unit: column.Unit, Value: value,
+				})
+			}
+		}
+	}
+	return cells
+}
+
+// rawSectionValue formats a value for machine-readable output: plain numbers,
+// RFC 3339 timestamps and ISO dates
+func rawSectionValue(v interface{}, typ providers.ColumnType) string {
+	if v == nil {
+		return ""
+	}
+	if f, ok := sectionFloat(v); ok {
+		return formatCSVFloat(f)
+	}
+	if t, ok := v.(time.Time); ok {
+		if typ == providers.ColumnDate {
+			return t.Format("2006-01-02")
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
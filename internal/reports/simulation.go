@@ -0,0 +1,155 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/simulate"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Simulation is the projected monthly cost delta of what-if scenarios, side by side
+type Simulation struct {
+	Results []simulate.Result
+}
+
+// Output writes the simulation in the given format (table, json or markdown) to
+// stdout, or to filePath when it is set
+func (s *Simulation) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return s.OutputTable(writer)
+	case "json":
+		return s.OutputJSON(writer)
+	case "markdown", "md":
+		return s.OutputMarkdown(writer)
+	default:
+		return fmt.Errorf("unsupported output format for simulation: %s (use table, json or markdown)", format)
+	}
+}
+
+var simulationColumns = []string{"Scenario", "Provider", "Change", "Resources", "Current/Month", "Projected/Month", "Delta", "Delta %"}
+
+// rows formats one row per scenario; scenarios that could not be evaluated show the error
+func (s *Simulation) rows() [][]string {
+	rows := make([][]string, 0, len(s.Results))
+	for _, r := range s.Results {
+		row := []string{r.Scenario.Name, r.Scenario.Provider, r.Scenario.Describe()}
+		if r.Error != "" {
+			rows = append(rows, append(row, "", "n/a", "n/a", r.Error, ""))
+			continue
+		}
+		rows = append(rows, append(row,
+			fmt.Sprintf("%d", r.Resources),
+			formatCurrency(r.Current, r.Currency),
+			formatCurrency(r.Projected, r.Currency),
+			formatSignedCurrency(r.Delta(), r.Currency),
+			formatDeltaPct(percentChange(r.Current, r.Projected)),
+		))
+	}
+	return rows
+}
+
+// notes lists the assumptions behind each scenario, e.g. "lambda-14d: ..."
+func (s *Simulation) notes() []string {
+	var notes []string
+	for _, r := range s.Results {
+		for _, note := range r.Notes {
+			notes = append(notes, fmt.Sprintf("%s: %s", r.Scenario.Name, note))
+		}
+	}
+	return notes
+}
+
+const simulationCaveat = "Scenarios are evaluated independently against today's usage; deltas of overlapping scenarios do not add up"
+
+// OutputTable writes the scenarios as a plain-text table followed by their notes
+func (s *Simulation) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "What-if simulation of %d scenarios\n%s\n\n", len(s.Results), simulationCaveat)
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(simulationColumns)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+	})
+	table.AppendBulk(s.rows())
+	table.Render()
+
+	if notes := s.notes(); len(notes) > 0 {
+		fmt.Fprintf(w, "\nNotes:\n  - %s\n", strings.Join(notes, "\n  - "))
+	}
+	return nil
+}
+
+// OutputMarkdown writes the scenarios as GitHub-flavored Markdown
+func (s *Simulation) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# What-if simulation\n\n")
+	md.printf("%s.\n\n", simulationCaveat)
+	md.table(simulationColumns, s.rows(), "lllrrrrr")
+
+	if notes := s.notes(); len(notes) > 0 {
+		md.printf("## Notes\n\n")
+		for _, note := range notes {
+			md.printf("- %s\n", note)
+		}
+		md.printf("\n")
+	}
+
+	return md.err
+}
+
+// OutputJSON writes the scenarios as JSON
+func (s *Simulation) OutputJSON(w io.Writer) error {
+	type jsonResult struct {
+		simulate.Scenario
+		Change    string   `json:"change"`
+		Resources int      `json:"resources"`
+		Current   float64  `json:"currentMonthlyCost"`
+		Projected float64  `json:"projectedMonthlyCost"`
+		Delta     float64  `json:"delta"`
+		DeltaPct  *float64 `json:"deltaPct"`
+		Currency  string   `json:"currency"`
+		Notes     []string `json:"notes,omitempty"`
+		Error     string   `json:"error,omitempty"`
+	}
+
+	output := struct {
+		Scenarios []jsonResult `json:"scenarios"`
+	}{Scenarios: []jsonResult{}}
+
+	for _, r := range s.Results {
+		result := jsonResult{
+			Scenario:  r.Scenario,
+			Change:    r.Scenario.Describe(),
+			Resources: r.Resources,
+			Currency:  r.Currency,
+			Notes:     r.Notes,
+			Error:     r.Error,
+		}
+		if r.Error == "" {
+			result.Current = r.Current
+			result.Projected = r.Projected
+			result.Delta = r.Delta()
+			result.DeltaPct = percentChange(r.Current, r.Projected)
+		}
+		output.Scenarios = append(output.Scenarios, result)
+	}
+
+	return WriteJSON(w, output)
+}
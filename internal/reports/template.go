@@ -0,0 +1,207 @@
+package reports
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// TemplateFuncs are the helper functions available to report templates:
+//
+//	currency AMOUNT CODE   "$1,234.56" for USD/EUR/GBP, "1,234.56 CHF" otherwise
+//	bytes BYTES            "1.5 GB" (decimal units, as providers bill them)
+//	percent RATIO          "12.5%" for 0.125
+//	number VALUE           "1,234.56"
+//	date TIME              "2006-01-02"
+//	top N GROUPS           the first N cost groups
+//	field FIELD            a section summary value formatted by its type
+//	cells TABLE ROW        the cells of a section table row formatted by column type
+//	numeric COLUMN         whether a section column holds numbers (for alignment)
+//	upper, lower, title    string case helpers
+func TemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"currency": formatCurrency,
+		"bytes":    formatBytes,
+		"percent":  formatPercent,
+		"number":   func(v float64) string { return formatThousands(v, 2) },
+		"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+		"top":      topCostGroups,
+		"field":    func(f providers.SectionField) string { return formatSectionValue(f.Value, f.Type, f.Unit) },
+		"cells":    formatSectionRow,
+		"numeric":  func(c providers.SectionColumn) bool { return isNumericColumn(c.Type) },
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"title":    titleCase,
+	}
+}
+
+// BuiltinTemplates lists the names of the templates shipped with the tool
+func BuiltinTemplates() []string {
+	files := builtinTemplateFiles()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// builtinTemplateFiles maps built-in template names to their embedded file names.
+// templates/email.html.tmpl is the built-in template "email".
+func builtinTemplateFiles() map[string]string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".tmpl") {
+			continue
+		}
+		name, _, _ := strings.Cut(entry.Name(), ".")
+		files[name] = entry.Name()
+	}
+	return files
+}
+
+// OutputTemplate renders the report view through a user-defined template. name is a
+// template file or the name of a built-in template. Templates whose file name
+// contains ".html" are parsed with html/template, so data is escaped for HTML;
+// everything else uses text/template.
+func (r *Report) OutputTemplate(w io.Writer, name string) error {
+	source, fileName, err := loadTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	view := r.View()
+
+	if strings.Contains(fileName, ".html") {
+		tmpl, err := htmltemplate.New(fileName).Funcs(TemplateFuncs()).Parse(source)
+		if err != nil {
+			return fmt.Errorf("error parsing template %s: %w", name, err)
+		}
+		if err := tmpl.Execute(w, view); err != nil {
+			return fmt.Errorf("error rendering template %s: %w", name, err)
+		}
+		return nil
+	}
+
+	tmpl, err := template.New(fileName).Funcs(TemplateFuncs()).Parse(source)
+	if err != nil {
+		return fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+	if err := tmpl.Execute(w, view); err != nil {
+		return fmt.Errorf("error rendering template %s: %w", name, err)
+	}
+	return nil
+}
+
+// loadTemplate reads a template file, falling back to the built-in template of that name
+func loadTemplate(name string) (source string, fileName string, err error) {
+	if data, err := os.ReadFile(name); err == nil {
+		return string(data), filepath.Base(name), nil
+	} else if !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("error reading template %s: %w", name, err)
+	}
+
+	if fileName, ok := builtinTemplateFiles()[name]; ok {
+		data, err := builtinTemplates.ReadFile(path.Join("templates", fileName))
+		if err != nil {
+			return "", "", fmt.Errorf("error reading built-in template %s: %w", name, err)
+		}
+		return string(data), fileName, nil
+	}
+
+	return "", "", fmt.Errorf("template %s not found (built-in templates: %s)", name, strings.Join(BuiltinTemplates(), ", "))
+}
+
+// formatCurrency formats an amount with thousands separators and its currency
+func formatCurrency(amount float64, code string) string {
+	return formatCurrencyDecimals(amount, code, 2)
+}
+
+// formatCurrencyDecimals formats an amount like formatCurrency, with the given decimals
+func formatCurrencyDecimals(amount float64, code string, decimals int) string {
+	value := formatThousands(math.Abs(amount), decimals)
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	switch strings.ToUpper(code) {
+	case "USD":
+		return sign + "$" + value
+	case "EUR":
+		return sign + "€" + value
+	case "GBP":
+		return sign + "£" + value
+	case "":
+		return sign + value
+	default:
+		return sign + value + " " + code
+	}
+}
+
+// formatBytes formats a byte count with decimal units
+func formatBytes(v float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for math.Abs(v) >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", v, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
+
+// formatPercent formats a 0-1 ratio as a percentage
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+// formatThousands formats v with the given decimals and comma thousands separators
+func formatThousands(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot:]
+	}
+
+	var b strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String() + fracPart
+}
+
+// topCostGroups returns at most n groups
+func topCostGroups(n int, groups []CostGroup) []CostGroup {
+	if n < len(groups) {
+		return groups[:n]
+	}
+	return groups
+}
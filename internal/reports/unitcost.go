@@ -0,0 +1,181 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ilhicas/observability-cost-center/internal/unitcost"
+	"github.com/olekukonko/tablewriter"
+)
+
+// UnitEconomics is the trend of unit economics KPIs over consecutive periods
+type UnitEconomics struct {
+	Provider string
+	Periods  []Period           // Oldest first
+	Values   [][]unitcost.Value // KPIs of each period, in the same order for every period
+}
+
+// Output writes the KPIs in the given format (table, json or markdown) to stdout, or
+// to filePath when it is set
+func (u *UnitEconomics) Output(format, filePath string) error {
+	var writer io.Writer = os.Stdout
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	switch format {
+	case "table", "summary":
+		return u.OutputTable(writer)
+	case "json":
+		return u.OutputJSON(writer)
+	case "markdown", "md":
+		return u.OutputMarkdown(writer)
+	default:
+		return fmt.Errorf("unsupported output format for unit costs: %s (use table, json or markdown)", format)
+	}
+}
+
+func (u *UnitEconomics) header() []string {
+	header := []string{"KPI", "Unit"}
+	for _, p := range u.Periods {
+		header = append(header, p.String())
+	}
+	return append(header, "Change")
+}
+
+// rows formats one row per KPI with its value in every period and the change over
+// the last period
+func (u *UnitEconomics) rows() [][]string {
+	if len(u.Values) == 0 {
+		return nil
+	}
+	last := len(u.Values) - 1
+
+	rows := make([][]string, 0, len(u.Values[last]))
+	for i, v := range u.Values[last] {
+		row := []string{v.KPI.Label(), v.KPI.UnitLabel()}
+		for _, period := range u.Values {
+			row = append(row, formatUnitCost(period[i].Value, period[i].Currency))
+		}
+
+		change := "n/a"
+		if last > 0 && v.Value != nil && u.Values[last-1][i].Value != nil {
+			change = formatDeltaPct(percentChange(*u.Values[last-1][i].Value, *v.Value))
+		}
+		rows = append(rows, append(row, change))
+	}
+	return rows
+}
+
+// formatUnitCost formats a cost per unit, keeping three significant digits for
+// fractions of a cent such as the cost of one metric
+func formatUnitCost(v *float64, currency string) string {
+	if v == nil {
+		return "n/a"
+	}
+	if *v == 0 || math.Abs(*v) >= 0.1 {
+		return formatCurrency(*v, currency)
+	}
+	decimals := int(math.Ceil(-math.Log10(math.Abs(*v)))) + 2
+	return formatCurrencyDecimals(*v, currency, decimals)
+}
+
+func (u *UnitEconomics) alignment() []int {
+	alignment := []int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT}
+	for range u.Periods {
+		alignment = append(alignment, tablewriter.ALIGN_RIGHT)
+	}
+	return append(alignment, tablewriter.ALIGN_RIGHT)
+}
+
+// OutputTable writes the KPIs as a plain-text table
+func (u *UnitEconomics) OutputTable(w io.Writer) error {
+	fmt.Fprintf(w, "Unit economics for %s\n", u.Provider)
+	fmt.Fprintf(w, "Change is the last period against the one before; n/a means there was no usage or business volume to divide by\n\n")
+
+	table := tablewriter.NewWriter(&writerAdapter{w: w})
+	table.SetHeader(u.header())
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(u.alignment())
+	table.AppendBulk(u.rows())
+	table.Render()
+
+	return nil
+}
+
+// OutputMarkdown writes the KPIs as GitHub-flavored Markdown
+func (u *UnitEconomics) OutputMarkdown(w io.Writer) error {
+	md := &markdownWriter{w: w}
+
+	md.printf("# Unit economics for %s\n\n", u.Provider)
+	align := "ll"
+	for range u.Periods {
+		align += "r"
+	}
+	md.table(u.header(), u.rows(), align+"r")
+	md.printf("Change is the last period against the one before.\n")
+
+	return md.err
+}
+
+// OutputJSON writes the KPIs as JSON, with the cost and quantity behind every value
+func (u *UnitEconomics) OutputJSON(w io.Writer) error {
+	type jsonValue struct {
+		Period    string   `json:"period"`
+		StartDate string   `json:"startDate"`
+		EndDate   string   `json:"endDate"`
+		Cost      float64  `json:"cost"`
+		Currency  string   `json:"currency"`
+		Quantity  float64  `json:"quantity"`
+		Value     *float64 `json:"value"`
+	}
+	type jsonKPI struct {
+		Name     string      `json:"name"`
+		Title    string      `json:"title"`
+		Unit     string      `json:"unit"`
+		Per      float64     `json:"per"`
+		Business string      `json:"business,omitempty"`
+		Change   *float64    `json:"change"`
+		Values   []jsonValue `json:"values"`
+	}
+
+	output := struct {
+		Provider string    `json:"provider"`
+		KPIs     []jsonKPI `json:"kpis"`
+	}{Provider: u.Provider, KPIs: []jsonKPI{}}
+
+	if len(u.Values) > 0 {
+		last := len(u.Values) - 1
+		for i, v := range u.Values[last] {
+			k := jsonKPI{Name: v.KPI.Name, Title: v.KPI.Label(), Unit: v.KPI.Unit, Per: v.KPI.Per, Business: v.KPI.Business}
+			if k.Per == 0 {
+				k.Per = 1
+			}
+			for p, period := range u.Values {
+				pv := period[i]
+				k.Values = append(k.Values, jsonValue{
+					Period:    u.Periods[p].String(),
+					StartDate: pv.Start.Format("2006-01-02"),
+					EndDate:   pv.End.Format("2006-01-02"),
+					Cost:      pv.Cost,
+					Currency:  pv.Currency,
+					Quantity:  pv.Quantity,
+					Value:     pv.Value,
+				})
+			}
+			if last > 0 && v.Value != nil && u.Values[last-1][i].Value != nil {
+				k.Change = percentChange(*u.Values[last-1][i].Value, *v.Value)
+			}
+			output.KPIs = append(output.KPIs, k)
+		}
+	}
+
+	return WriteJSON(w, output)
+}
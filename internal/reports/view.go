@@ -0,0 +1,150 @@
+package reports
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// ReportView is the data handed to user-defined report templates (--template). Fields
+// are only ever added, so templates keep working across releases.
+type ReportView struct {
+	Provider    string
+	ReportType  string
+	StartDate   time.Time
+	EndDate     time.Time
+	GeneratedAt time.Time
+
+	// TotalCost is the sum of all cost entries. Currency is the currency they share,
+	// or "" when the report mixes currencies.
+	TotalCost float64
+	Currency  string
+
+	UsageCount int
+	CostCount  int
+
+	// Accounts, Services and Days total the cost entries. Accounts and Services are
+	// ordered by cost, highest first, and Days chronologically. Each group breaks its
+	// cost down further: accounts and days by service, services by account.
+	Accounts []CostGroup
+	Services []CostGroup
+	Days     []CostGroup
+
+	Licenses []LicenseView
+
+	// Raw entries, in the order the provider returned them
+	Usage []providers.UsageData
+	Costs []providers.CostData
+
+	// Sections are the provider sections, in report order
+	Sections []SectionView
+}
+
+// CostGroup is the cost total of one account, service or day
+type CostGroup struct {
+	Name      string
+	Date      time.Time // Set for day groups
+	Cost      float64
+	Currency  string
+	Share     float64 // Share of the report total (of the parent group in a breakdown), 0-1
+	Entries   int
+	Breakdown []CostGroup
+}
+
+// LicenseView summarizes one license type
+type LicenseView struct {
+	Type        string
+	Used        float64
+	Total       float64
+	Utilization float64 // 0-1
+}
+
+// SectionView is a report section. Content is the section rendered as plain text,
+// for templates that don't walk the summary, findings and tables themselves.
+type SectionView struct {
+	providers.Section
+	Content string
+}
+
+// View builds the template view model of the report
+func (r *Report) View() ReportView {
+	view := ReportView{
+		Provider:    r.ProviderName,
+		ReportType:  r.ReportType,
+		StartDate:   r.StartDate,
+		EndDate:     r.EndDate,
+		GeneratedAt: time.Now().UTC(),
+		TotalCost:   r.TotalCost,
+		Currency:    reportCurrency(r.CostData),
+		UsageCount:  len(r.UsageData),
+		CostCount:   len(r.CostData),
+		Usage:       r.UsageData,
+		Costs:       r.CostData,
+	}
+
+	accountOf := func(c providers.CostData) string { return c.AccountID }
+	serviceOf := func(c providers.CostData) string { return c.Service }
+	dayOf := func(c providers.CostData) string { return c.StartTime.Format("2006-01-02") }
+
+	view.Accounts = groupCosts(r.CostData, r.TotalCost, accountOf, serviceOf)
+	view.Services = groupCosts(r.CostData, r.TotalCost, serviceOf, accountOf)
+	view.Days = groupCosts(r.CostData, r.TotalCost, dayOf, serviceOf)
+	for i := range view.Days {
+		view.Days[i].Date, _ = time.Parse("2006-01-02", view.Days[i].Name)
+	}
+	sort.SliceStable(view.Days, func(i, j int) bool { return view.Days[i].Name < view.Days[j].Name })
+
+	for _, u := range r.UsageData {
+		if u.Service != "Licenses" {
+			continue
+		}
+		view.Licenses = append(view.Licenses, LicenseView{
+			Type:        strings.TrimSuffix(u.Metric, " Licenses"),
+			Used:        u.Value,
+			Total:       metadataFloat(u.Metadata, "totalLicenses"),
+			Utilization: metadataFloat(u.Metadata, "utilizationPct") / 100,
+		})
+	}
+
+	for _, section := range r.orderedSections() {
+		var content strings.Builder
+		writeSectionBody(&content, section)
+		view.Sections = append(view.Sections, SectionView{Section: section, Content: strings.TrimSpace(content.String())})
+	}
+
+	return view
+}
+
+// groupCosts totals costs by key, breaking every group down by subKey. Groups are
+// ordered by cost, highest first.
+func groupCosts(costs []providers.CostData, total float64, key, subKey func(providers.CostData) string) []CostGroup {
+	groups := make(map[string][]providers.CostData)
+	var order []string
+	for _, c := range costs {
+		k := key(c)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], c)
+	}
+
+	result := make([]CostGroup, 0, len(order))
+	for _, k := range order {
+		group := CostGroup{Name: k, Currency: reportCurrency(groups[k]), Entries: len(groups[k])}
+		for _, c := range groups[k] {
+			group.Cost += c.Cost
+		}
+		if total != 0 {
+			group.Share = group.Cost / total
+		}
+		if subKey != nil {
+			group.Breakdown = groupCosts(groups[k], group.Cost, subKey, nil)
+		}
+		result = append(result, group)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Cost > result[j].Cost })
+	return result
+}
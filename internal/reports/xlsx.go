@@ -0,0 +1,544 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/xuri/excelize/v2"
+)
+
+// XLSX sheet names, in workbook order
+const (
+	XLSXSheetSummary       = "Summary"
+	XLSXSheetUsage         = "Usage"
+	XLSXSheetCostByDay     = "Cost by Day"
+	XLSXSheetCostByAccount = "Cost by Account"
+	XLSXSheetLicenses      = "Licenses"
+)
+
+// xlsxWriter fills a workbook and caches cell styles, since excelize creates a new
+// style entry for every AddStyle call
+type xlsxWriter struct {
+	f          *excelize.File
+	header     int
+	date       int
+	dateTime   int
+	number     int
+	percent    int
+	currencies map[string]int
+}
+
+// OutputXLSX writes the report as an Excel workbook with Summary, Usage, Cost by Day,
+// Cost by Account and Licenses sheets. Numbers and dates are stored as typed cells,
+// costs carry a currency format and every header row is frozen.
+func (r *Report) OutputXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	x, err := newXLSXWriter(f)
+	if err != nil {
+		return err
+	}
+
+	// Every sheet is always present so workbooks from different runs line up
+	sheets := []struct {
+		name  string
+		write func(*xlsxWriter, string) error
+	}{
+		{XLSXSheetSummary, r.writeXLSXSummary},
+		{XLSXSheetUsage, r.writeXLSXUsage},
+		{XLSXSheetCostByDay, r.writeXLSXCostByDay},
+		{XLSXSheetCostByAccount, r.writeXLSXCostByAccount},
+		{XLSXSheetLicenses, r.writeXLSXLicenses},
+	}
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.name); err != nil {
+				return fmt.Errorf("error naming sheet %s: %w", sheet.name, err)
+			}
+		} else if _, err := f.NewSheet(sheet.name); err != nil {
+			return fmt.Errorf("error creating sheet %s: %w", sheet.name, err)
+		}
+
+		if err := sheet.write(x, sheet.name); err != nil {
+			return fmt.Errorf("error writing sheet %s: %w", sheet.name, err)
+		}
+	}
+
+	// Report sections follow the fixed sheets, one sheet each
+	used := make(map[string]bool)
+	for _, sheet := range sheets {
+		used[strings.ToLower(sheet.name)] = true
+	}
+	for _, section := range r.orderedSections() {
+		name := xlsxSheetName(section.Title, used)
+		if _, err := f.NewSheet(name); err != nil {
+			return fmt.Errorf("error creating sheet %s: %w", name, err)
+		}
+		if err := x.writeSection(name, section); err != nil {
+			return fmt.Errorf("error writing sheet %s: %w", name, err)
+		}
+	}
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("error writing XLSX workbook: %w", err)
+	}
+	return nil
+}
+
+func newXLSXWriter(f *excelize.File) (*xlsxWriter, error) {
+	x := &xlsxWriter{f: f, currencies: make(map[string]int)}
+
+	styles := []struct {
+		id    *int
+		style excelize.Style
+	}{
+		{&x.header, excelize.Style{
+			Font: &excelize.Font{Bold: true},
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+		}},
+		{&x.date, excelize.Style{CustomNumFmt: xlsxString("yyyy-mm-dd")}},
+		{&x.dateTime, excelize.Style{CustomNumFmt: xlsxString("yyyy-mm-dd hh:mm:ss")}},
+		{&x.number, excelize.Style{CustomNumFmt: xlsxString("#,##0.00")}},
+		{&x.percent, excelize.Style{CustomNumFmt: xlsxString("0.0%")}},
+	}
+
+	for _, s := range styles {
+		id, err := f.NewStyle(&s.style)
+		if err != nil {
+			return nil, fmt.Errorf("error creating XLSX style: %w", err)
+		}
+		*s.id = id
+	}
+
+	return x, nil
+}
+
+// currencyStyle returns the number format for amounts in the given currency
+func (x *xlsxWriter) currencyStyle(currency string) (int, error) {
+	if id, ok := x.currencies[currency]; ok {
+		return id, nil
+	}
+
+	format := `#,##0.00`
+	switch strings.ToUpper(currency) {
+	case "":
+	case "USD":
+		format = `"$"#,##0.00`
+	case "EUR":
+		format = `"€"#,##0.00`
+	case "GBP":
+		format = `"£"#,##0.00`
+	default:
+		format = fmt.Sprintf(`#,##0.00 "%s"`, strings.ReplaceAll(currency, `"`, ""))
+	}
+
+	id, err := x.f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		return 0, fmt.Errorf("error creating currency style: %w", err)
+	}
+	x.currencies[currency] = id
+	return id, nil
+}
+
+// writeHeader writes the header row, freezes it and sets the column widths
+func (x *xlsxWriter) writeHeader(sheet string, columns []string, widths []float64) error {
+	for i, column := range columns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := x.f.SetCellStr(sheet, cell, column); err != nil {
+			return err
+		}
+
+		if i < len(widths) {
+			name, _ := excelize.ColumnNumberToName(i + 1)
+			if err := x.f.SetColWidth(sheet, name, name, widths[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	last, _ := excelize.CoordinatesToCellName(len(columns), 1)
+	if err := x.f.SetCellStyle(sheet, "A1", last, x.header); err != nil {
+		return err
+	}
+
+	return x.f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+// setCell writes a value to a cell and applies a style when one is given
+func (x *xlsxWriter) setCell(sheet string, col, row int, value interface{}, style int) error {
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	if err := x.f.SetCellValue(sheet, cell, value); err != nil {
+		return err
+	}
+	if style != 0 {
+		return x.f.SetCellStyle(sheet, cell, cell, style)
+	}
+	return nil
+}
+
+// setRow writes the cells of a row in order. Values of type xlsxStyled carry their own style.
+func (x *xlsxWriter) setRow(sheet string, row int, values ...interface{}) error {
+	for i, value := range values {
+		style := 0
+		if styled, ok := value.(xlsxStyled); ok {
+			value, style = styled.value, styled.style
+		}
+		if err := x.setCell(sheet, i+1, row, value, style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xlsxStyled pairs a cell value with a style ID
+type xlsxStyled struct {
+	value interface{}
+	style int
+}
+
+func (r *Report) writeXLSXSummary(x *xlsxWriter, sheet string) error {
+	if err := x.writeHeader(sheet, []string{"Field", "Value", "Currency"}, []float64{24, 24, 10}); err != nil {
+		return err
+	}
+
+	currency := reportCurrency(r.CostData)
+	totalStyle, err := x.currencyStyle(currency)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]interface{}{
+		{"Provider", r.ProviderName},
+		{"Report type", r.ReportType},
+		{"Start date", xlsxStyled{r.StartDate, x.date}},
+		{"End date", xlsxStyled{r.EndDate, x.date}},
+		{"Total cost", xlsxStyled{r.TotalCost, totalStyle}, currency},
+		{"Usage entries", len(r.UsageData)},
+		{"Cost entries", len(r.CostData)},
+	}
+	for i, row := range rows {
+		if err := x.setRow(sheet, i+2, row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Report) writeXLSXUsage(x *xlsxWriter, sheet string) error {
+	columns := []string{"Provider", "Service", "Metric", "Value", "Unit", "Timestamp", "Account ID"}
+	if err := x.writeHeader(sheet, columns, []float64{12, 24, 36, 14, 12, 20, 14}); err != nil {
+		return err
+	}
+
+	row := 2
+	for _, u := range r.UsageData {
+		if u.Service == "Licenses" {
+			continue
+		}
+		err := x.setRow(sheet, row,
+			r.ProviderName,
+			u.Service,
+			u.Metric,
+			xlsxStyled{u.Value, x.number},
+			u.Unit,
+			xlsxStyled{u.Timestamp.UTC(), x.dateTime},
+			metadataString(u.Metadata, "accountId"),
+		)
+		if err != nil {
+			return err
+		}
+		row++
+	}
+	return nil
+}
+
+// writeXLSXCostByDay totals cost per day, account and service
+func (r *Report) writeXLSXCostByDay(x *xlsxWriter, sheet string) error {
+	columns := []string{"Date", "Account ID", "Service", "Cost", "Currency", "Quantity", "Usage Unit"}
+	if err := x.writeHeader(sheet, columns, []float64{12, 28, 28, 14, 10, 14, 12}); err != nil {
+		return err
+	}
+
+	type dayKey struct {
+		day                        time.Time
+		account, service, currency string
+		unit                       string
+	}
+	totals := make(map[dayKey][2]float64)
+	var keys []dayKey
+	for _, c := range r.CostData {
+		day := time.Date(c.StartTime.Year(), c.StartTime.Month(), c.StartTime.Day(), 0, 0, 0, 0, time.UTC)
+		key := dayKey{day: day, account: c.AccountID, service: c.Service, currency: c.Currency, unit: c.UsageUnit}
+		if _, ok := totals[key]; !ok {
+			keys = append(keys, key)
+		}
+		t := totals[key]
+		t[0] += c.Cost
+		t[1] += c.Quantity
+		totals[key] = t
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		if !keys[i].day.Equal(keys[j].day) {
+			return keys[i].day.Before(keys[j].day)
+		}
+		if keys[i].account != keys[j].account {
+			return keys[i].account < keys[j].account
+		}
+		return keys[i].service < keys[j].service
+	})
+
+	for i, key := range keys {
+		style, err := x.currencyStyle(key.currency)
+		if err != nil {
+			return err
+		}
+		err = x.setRow(sheet, i+2,
+			xlsxStyled{key.day, x.date},
+			key.account,
+			key.service,
+			xlsxStyled{totals[key][0], style},
+			key.currency,
+			xlsxStyled{totals[key][1], x.number},
+			key.unit,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeXLSXCostByAccount totals cost per account and service
+func (r *Report) writeXLSXCostByAccount(x *xlsxWriter, sheet string) error {
+	columns := []string{"Account ID", "Service", "Cost", "Currency", "Share of Account"}
+	if err := x.writeHeader(sheet, columns, []float64{28, 28, 14, 10, 16}); err != nil {
+		return err
+	}
+
+	type accountKey struct {
+		account, service, currency string
+	}
+	totals := make(map[accountKey]float64)
+	accountTotals := make(map[string]float64)
+	for _, c := range r.CostData {
+		totals[accountKey{c.AccountID, c.Service, c.Currency}] += c.Cost
+		accountTotals[c.AccountID] += c.Cost
+	}
+
+	keys := make([]accountKey, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].account != keys[j].account {
+			return keys[i].account < keys[j].account
+		}
+		if keys[i].service != keys[j].service {
+			return keys[i].service < keys[j].service
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	for i, key := range keys {
+		style, err := x.currencyStyle(key.currency)
+		if err != nil {
+			return err
+		}
+		share := 0.0
+		if accountTotals[key.account] != 0 {
+			share = totals[key] / accountTotals[key.account]
+		}
+		err = x.setRow(sheet, i+2,
+			key.account,
+			key.service,
+			xlsxStyled{totals[key], style},
+			key.currency,
+			xlsxStyled{share, x.percent},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Report) writeXLSXLicenses(x *xlsxWriter, sheet string) error {
+	columns := []string{"Provider", "License Type", "Used", "Total", "Utilization"}
+	if err := x.writeHeader(sheet, columns, []float64{12, 24, 10, 10, 12}); err != nil {
+		return err
+	}
+
+	row := 2
+	for _, u := range r.UsageData {
+		if u.Service != "Licenses" {
+			continue
+		}
+		err := x.setRow(sheet, row,
+			r.ProviderName,
+			strings.TrimSuffix(u.Metric, " Licenses"),
+			u.Value,
+			metadataFloat(u.Metadata, "totalLicenses"),
+			xlsxStyled{metadataFloat(u.Metadata, "utilizationPct") / 100, x.percent},
+		)
+		if err != nil {
+			return err
+		}
+		row++
+	}
+	return nil
+}
+
+// writeSection lays out a report section top to bottom: summary fields, findings,
+// then each table under its title
+func (x *xlsxWriter) writeSection(sheet string, section providers.Section) error {
+	if err := x.setCell(sheet, 1, 1, section.Title, x.header); err != nil {
+		return err
+	}
+	if err := x.f.SetColWidth(sheet, "A", "A", 28); err != nil {
+		return err
+	}
+	row := 3
+
+	for _, field := range section.Summary {
+		value, err := x.typedCell(field.Value, field.Type, field.Unit)
+		if err != nil {
+			return err
+		}
+		if err := x.setRow(sheet, row, field.Label, value); err != nil {
+			return err
+		}
+		row++
+	}
+	if len(section.Summary) > 0 {
+		row++
+	}
+
+	for _, f := range section.Findings {
+		var impact interface{}
+		if f.Impact > 0 {
+			cell, err := x.typedCell(f.Impact, providers.ColumnCurrency, f.Currency)
+			if err != nil {
+				return err
+			}
+			impact = cell
+		}
+		if err := x.setRow(sheet, row, strings.ToUpper(f.Severity), f.Message, impact, f.Detail); err != nil {
+			return err
+		}
+		row++
+	}
+	if len(section.Findings) > 0 {
+		row++
+	}
+
+	for _, t := range section.Tables {
+		if err := x.setCell(sheet, 1, row, t.Title, 0); err != nil {
+			return err
+		}
+		row++
+
+		for i, column := range t.Columns {
+			if err := x.setCell(sheet, i+1, row, column.Title, x.header); err != nil {
+				return err
+			}
+		}
+		row++
+
+		for _, values := range t.Rows {
+			for i, column := range t.Columns {
+				if i >= len(values) || values[i] == nil {
+					continue
+				}
+				cell, err := x.typedCell(values[i], column.Type, column.Unit)
+				if err != nil {
+					return err
+				}
+				if err := x.setCell(sheet, i+1, row, cell.value, cell.style); err != nil {
+					return err
+				}
+			}
+			row++
+		}
+		row++
+	}
+
+	for _, note := range section.Notes {
+		if err := x.setCell(sheet, 1, row, note, 0); err != nil {
+			return err
+		}
+		row++
+	}
+	return nil
+}
+
+// typedCell styles a section value according to its column type
+func (x *xlsxWriter) typedCell(value interface{}, typ providers.ColumnType, unit string) (xlsxStyled, error) {
+	switch typ {
+	case providers.ColumnCurrency:
+		style, err := x.currencyStyle(unit)
+		if err != nil {
+			return xlsxStyled{}, err
+		}
+		return xlsxStyled{value, style}, nil
+	case providers.ColumnPercent:
+		return xlsxStyled{value, x.percent}, nil
+	case providers.ColumnNumber:
+		return xlsxStyled{value, x.number}, nil
+	case providers.ColumnDate:
+		return xlsxStyled{value, x.date}, nil
+	case providers.ColumnDateTime:
+		return xlsxStyled{value, x.dateTime}, nil
+	}
+	return xlsxStyled{value, 0}, nil
+}
+
+// xlsxSheetName turns a section title into a unique sheet name. Excel limits names
+// to 31 characters and forbids : \ / ? * [ ]
+func xlsxSheetName(title string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+	name = truncateRunes(strings.TrimSpace(name), 31)
+	if name == "" {
+		name = "Section"
+	}
+
+	candidate := name
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, 31-len(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+func xlsxString(s string) *string {
+	return &s
+}
@@ -0,0 +1,82 @@
+package simulate
+
+import "strings"
+
+// Kinds of baseline resources
+const (
+	KindLogGroup  = "logGroup"  // AWS CloudWatch Logs log group
+	KindEventType = "eventType" // New Relic event type
+	KindNamespace = "namespace" // New Relic retention namespace
+	KindLicense   = "license"   // New Relic license type
+)
+
+// Resource is the current usage of something a scenario can change
+type Resource struct {
+	Kind      string
+	AccountID string
+	Name      string
+
+	MonthlyGB     float64 // Ingested over the last 30 days
+	StoredGB      float64 // Currently stored (log groups)
+	RetentionDays int     // 0 never expires
+	Class         string  // Log group class
+	MetricFilters int     // Metric filters on a log group
+	Count         float64 // Users of a license type
+}
+
+// Pricing is the price list the baseline is valued at
+type Pricing struct {
+	Currency string
+
+	// Ingest prices per GB, by log class for AWS
+	IngestPerGB                 float64
+	InfrequentAccessIngestPerGB float64
+
+	// StoragePerGBMonth prices stored log data (AWS). New Relic instead bills each GB
+	// kept beyond IncludedRetentionDays at RetentionPerGBMonth per extra 30 days.
+	StoragePerGBMonth     float64
+	IncludedRetentionDays int
+	RetentionPerGBMonth   float64
+
+	Licenses map[string]float64 // Monthly price per license type
+}
+
+// Baseline is a provider's current usage and pricing
+type Baseline struct {
+	Provider  string
+	Resources []Resource
+	Pricing   Pricing
+}
+
+// BaselineProvider is implemented by providers that can describe their current usage
+// for simulation
+type BaselineProvider interface {
+	SimulationBaseline() (*Baseline, error)
+}
+
+// ingestPrice is the price per GB ingested into a log group of the given class
+func (p Pricing) ingestPrice(class string) float64 {
+	if class == ClassInfrequentAccess {
+		return p.InfrequentAccessIngestPerGB
+	}
+	return p.IngestPerGB
+}
+
+// licensePrice returns the monthly price of a license type, matched case-insensitively
+func (p Pricing) licensePrice(licenseType string) (float64, bool) {
+	for name, price := range p.Licenses {
+		if strings.EqualFold(name, licenseType) {
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+// extendedRetentionCost is the monthly New Relic cost of keeping monthlyGB for days
+func (p Pricing) extendedRetentionCost(monthlyGB float64, days int) float64 {
+	extra := days - p.IncludedRetentionDays
+	if extra <= 0 {
+		return 0
+	}
+	return monthlyGB * float64(extra) / 30 * p.RetentionPerGBMonth
+}
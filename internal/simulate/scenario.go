@@ -0,0 +1,205 @@
+// Package simulate projects the monthly cost delta of what-if scenarios, such as
+// shorter log retention or dropping a share of events, by applying them to the
+// current usage and pricing of a provider.
+package simulate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Scenario types
+const (
+	// TypeRetention changes the retention of log groups (AWS) or namespaces (New Relic)
+	TypeRetention = "retention"
+
+	// TypeDrop drops a percentage of the data ingested by log groups or event types
+	TypeDrop = "drop"
+
+	// TypeDowngrade moves New Relic users from one license type to another
+	TypeDowngrade = "downgrade"
+
+	// TypeLogClass moves log groups to another CloudWatch Logs class
+	TypeLogClass = "log_class"
+)
+
+// CloudWatch Logs log group classes
+const (
+	ClassStandard         = "STANDARD"
+	ClassInfrequentAccess = "INFREQUENT_ACCESS"
+)
+
+// Scenario is a what-if change to the current usage
+type Scenario struct {
+	Name     string `mapstructure:"name" json:"name"`
+	Type     string `mapstructure:"type" json:"type"`
+	Provider string `mapstructure:"provider" json:"provider"` // Defaults by type, or to the configured provider
+
+	// Account and Match select resources with providers.MatchPattern: log group names
+	// for AWS, event types or retention namespaces for New Relic. Empty matches all.
+	Account string `mapstructure:"account" json:"account,omitempty"`
+	Match   string `mapstructure:"match" json:"match,omitempty"`
+
+	Days    int     `mapstructure:"days" json:"days,omitempty"`       // retention: new retention in days
+	Percent float64 `mapstructure:"percent" json:"percent,omitempty"` // drop: share of the data dropped, 0-100
+	Count   int     `mapstructure:"count" json:"count,omitempty"`     // downgrade: users to move, 0 for all
+	From    string  `mapstructure:"from" json:"from,omitempty"`       // downgrade: current license type
+	To      string  `mapstructure:"to" json:"to,omitempty"`           // downgrade: new license type; log_class: new class
+}
+
+// ScenariosFromViper reads simulate.scenarios, or top-level scenarios in a standalone
+// scenarios file.
+//
+//	simulate:
+//	  scenarios:
+//	    - name: lambda-14d
+//	      type: retention
+//	      match: /aws/lambda/*
+//	      days: 14
+//	    - name: drop-debug-logs
+//	      type: drop
+//	      provider: newrelic
+//	      account: "1234567"
+//	      match: Log
+//	      percent: 30
+//	    - name: full-to-core
+//	      type: downgrade
+//	      from: Full platform
+//	      to: Core
+//	      count: 20
+//	    - name: vpc-flow-logs-ia
+//	      type: log_class
+//	      match: /vpc/flow-logs/*
+//	      to: INFREQUENT_ACCESS
+func ScenariosFromViper(config *viper.Viper, defaultProvider string) ([]Scenario, error) {
+	key := "simulate.scenarios"
+	if !config.IsSet(key) {
+		key = "scenarios"
+	}
+
+	var scenarios []Scenario
+	if err := config.UnmarshalKey(key, &scenarios); err != nil {
+		return nil, fmt.Errorf("error reading scenarios: %w", err)
+	}
+
+	names := make(map[string]bool, len(scenarios))
+	for i := range scenarios {
+		s := &scenarios[i]
+		if s.Name == "" {
+			s.Name = fmt.Sprintf("scenario-%d", i+1)
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("duplicate scenario %q", s.Name)
+		}
+		names[s.Name] = true
+		if err := s.normalize(defaultProvider); err != nil {
+			return nil, fmt.Errorf("invalid scenario %q: %w", s.Name, err)
+		}
+	}
+
+	return scenarios, nil
+}
+
+// LoadScenariosFile reads scenarios from a standalone YAML file
+func LoadScenariosFile(path, defaultProvider string) ([]Scenario, error) {
+	config := viper.New()
+	config.SetConfigFile(path)
+	if err := config.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading scenarios file: %w", err)
+	}
+	return ScenariosFromViper(config, defaultProvider)
+}
+
+// normalize validates the scenario and fills in the provider its type implies
+func (s *Scenario) normalize(defaultProvider string) error {
+	s.Type = strings.ToLower(s.Type)
+	s.Provider = strings.ToLower(s.Provider)
+
+	implied := ""
+	switch s.Type {
+	case TypeRetention:
+		if s.Days <= 0 {
+			return fmt.Errorf("a retention scenario needs days")
+		}
+	case TypeDrop:
+		if s.Percent <= 0 || s.Percent > 100 {
+			return fmt.Errorf("a drop scenario needs a percent between 0 and 100, got %g", s.Percent)
+		}
+	case TypeDowngrade:
+		implied = "newrelic"
+		if s.From == "" || s.To == "" {
+			return fmt.Errorf("a downgrade scenario needs from and to license types")
+		}
+		if s.Count < 0 {
+			return fmt.Errorf("count must not be negative, got %d", s.Count)
+		}
+	case TypeLogClass:
+		implied = "aws"
+		s.To = strings.ToUpper(s.To)
+		if s.To == "" {
+			s.To = ClassInfrequentAccess
+		}
+		if s.To != ClassStandard && s.To != ClassInfrequentAccess {
+			return fmt.Errorf("unknown log class %q (use %s or %s)", s.To, ClassStandard, ClassInfrequentAccess)
+		}
+	default:
+		return fmt.Errorf("unknown type %q (use %s, %s, %s or %s)", s.Type, TypeRetention, TypeDrop, TypeDowngrade, TypeLogClass)
+	}
+
+	if s.Provider == "" {
+		s.Provider = implied
+	}
+	if s.Provider == "" {
+		s.Provider = strings.ToLower(defaultProvider)
+	}
+	if s.Provider == "" {
+		return fmt.Errorf("no provider; set provider on the scenario or use --provider")
+	}
+	if implied != "" && s.Provider != implied {
+		return fmt.Errorf("%s scenarios only apply to %s", s.Type, implied)
+	}
+	return nil
+}
+
+// Describe summarizes the change, e.g. "retention of /aws/lambda/* to 14 days"
+func (s Scenario) Describe() string {
+	target := s.Match
+	if target == "" {
+		target = "all"
+	}
+	if s.Account != "" {
+		target += " in account " + s.Account
+	}
+
+	switch s.Type {
+	case TypeRetention:
+		return fmt.Sprintf("retention of %s to %d days", target, s.Days)
+	case TypeDrop:
+		return fmt.Sprintf("drop %g%% of %s", s.Percent, target)
+	case TypeDowngrade:
+		users := "all"
+		if s.Count > 0 {
+			users = fmt.Sprint(s.Count)
+		}
+		return fmt.Sprintf("downgrade %s %s users to %s", users, s.From, s.To)
+	case TypeLogClass:
+		return fmt.Sprintf("move %s to %s", target, s.To)
+	default:
+		return s.Type
+	}
+}
+
+// Providers returns the providers the scenarios apply to, in order of first use
+func Providers(scenarios []Scenario) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, s := range scenarios {
+		if !seen[s.Provider] {
+			seen[s.Provider] = true
+			names = append(names, s.Provider)
+		}
+	}
+	return names
+}
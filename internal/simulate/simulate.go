@@ -0,0 +1,174 @@
+package simulate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Result is the projected monthly effect of one scenario
+type Result struct {
+	Scenario  Scenario
+	Resources int     // Resources the scenario changes
+	Current   float64 // Current monthly cost of what the scenario changes
+	Projected float64 // Monthly cost of the same after the change
+	Currency  string
+	Notes     []string // Assumptions and caveats of the estimate
+	Error     string   // Set when the scenario could not be evaluated
+}
+
+// Delta is the projected change in monthly cost; negative is a saving
+func (r Result) Delta() float64 {
+	return r.Projected - r.Current
+}
+
+// Apply projects a scenario onto a baseline. The baseline is not modified.
+func Apply(s Scenario, b *Baseline) Result {
+	result := Result{Scenario: s, Currency: b.Pricing.Currency}
+
+	switch s.Type {
+	case TypeRetention:
+		applyRetention(&result, b)
+	case TypeDrop:
+		applyDrop(&result, b)
+	case TypeDowngrade:
+		applyDowngrade(&result, b)
+	case TypeLogClass:
+		applyLogClass(&result, b)
+	default:
+		result.Error = fmt.Sprintf("unknown scenario type %q", s.Type)
+	}
+
+	if result.Error == "" && result.Resources == 0 {
+		result.Notes = append(result.Notes, "no resources matched")
+	}
+	return result
+}
+
+// selects reports whether the scenario's account and match patterns select a resource
+func (s Scenario) selects(r Resource) bool {
+	return providers.MatchPattern(s.Account, r.AccountID) && providers.MatchPattern(s.Match, r.Name)
+}
+
+// applyRetention values the data kept under the new retention. AWS log groups keep
+// their stored volume in proportion to retention; New Relic bills each GB kept beyond
+// the included retention.
+func applyRetention(result *Result, b *Baseline) {
+	s, p := result.Scenario, b.Pricing
+	neverExpire := 0
+	for _, r := range b.Resources {
+		if !s.selects(r) || r.RetentionDays == s.Days {
+			continue
+		}
+		switch r.Kind {
+		case KindLogGroup:
+			stored := r.StoredGB * float64(s.Days) / float64(r.RetentionDays)
+			if r.RetentionDays == 0 {
+				stored = math.Min(r.StoredGB, r.MonthlyGB*float64(s.Days)/30)
+				neverExpire++
+			}
+			result.Current += r.StoredGB * p.StoragePerGBMonth
+			result.Projected += stored * p.StoragePerGBMonth
+		case KindNamespace:
+			result.Current += p.extendedRetentionCost(r.MonthlyGB, r.RetentionDays)
+			result.Projected += p.extendedRetentionCost(r.MonthlyGB, s.Days)
+		default:
+			continue
+		}
+		result.Resources++
+	}
+
+	if neverExpire > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("%d log groups never expire; their stored data is estimated from %d days of ingest", neverExpire, s.Days))
+	}
+	if result.Projected < result.Current {
+		result.Notes = append(result.Notes, "shorter retention saves in full once the older data has expired")
+	}
+}
+
+// applyDrop removes a share of the ingested data and, for log groups, of the data stored
+func applyDrop(result *Result, b *Baseline) {
+	s, p := result.Scenario, b.Pricing
+	kept := 1 - s.Percent/100
+	for _, r := range b.Resources {
+		if !s.selects(r) {
+			continue
+		}
+		var cost float64
+		switch r.Kind {
+		case KindLogGroup:
+			cost = r.MonthlyGB*p.ingestPrice(r.Class) + r.StoredGB*p.StoragePerGBMonth
+		case KindEventType:
+			cost = r.MonthlyGB * p.IngestPerGB
+		default:
+			continue
+		}
+		result.Current += cost
+		result.Projected += cost * kept
+		result.Resources++
+	}
+
+	if result.Resources > 0 && b.Provider == "newrelic" {
+		result.Notes = append(result.Notes, "event type volumes are bytecountestimate() estimates")
+	}
+}
+
+// applyDowngrade moves users between license types at their list prices
+func applyDowngrade(result *Result, b *Baseline) {
+	s, p := result.Scenario, b.Pricing
+	fromPrice, ok := p.licensePrice(s.From)
+	if !ok {
+		result.Error = fmt.Sprintf("no price for license type %q", s.From)
+		return
+	}
+	toPrice, ok := p.licensePrice(s.To)
+	if !ok {
+		result.Error = fmt.Sprintf("no price for license type %q", s.To)
+		return
+	}
+
+	users := 0.0
+	for _, r := range b.Resources {
+		if r.Kind == KindLicense && strings.EqualFold(r.Name, s.From) && providers.MatchPattern(s.Account, r.AccountID) {
+			users += r.Count
+		}
+	}
+
+	moved := users
+	if s.Count > 0 && float64(s.Count) < users {
+		moved = float64(s.Count)
+	} else if s.Count > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("only %.0f users have a %s license", users, s.From))
+	}
+
+	result.Resources = int(moved)
+	result.Current = moved * fromPrice
+	result.Projected = moved * toPrice
+}
+
+// applyLogClass reprices the ingest of log groups in another log class. Storage is
+// priced the same in both classes.
+func applyLogClass(result *Result, b *Baseline) {
+	s, p := result.Scenario, b.Pricing
+	withFilters := 0
+	for _, r := range b.Resources {
+		if r.Kind != KindLogGroup || !s.selects(r) || strings.EqualFold(r.Class, s.To) {
+			continue
+		}
+		result.Current += r.MonthlyGB * p.ingestPrice(r.Class)
+		result.Projected += r.MonthlyGB * p.ingestPrice(s.To)
+		result.Resources++
+		if r.MetricFilters > 0 {
+			withFilters++
+		}
+	}
+
+	if result.Resources > 0 {
+		result.Notes = append(result.Notes, "the class of an existing log group cannot be changed; the logs must be sent to new log groups")
+	}
+	if s.To == ClassInfrequentAccess && withFilters > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("%d of the log groups have metric filters, which Infrequent Access does not support", withFilters))
+	}
+}
@@ -0,0 +1,114 @@
+package unitcost
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Business holds business volumes by metric, such as requests served or customers,
+// used as KPI denominators
+type Business map[string][]BusinessValue
+
+// BusinessValue is a business volume for a day or a calendar month
+type BusinessValue struct {
+	Start time.Time
+	End   time.Time // Exclusive
+	Value float64
+}
+
+// LoadBusinessCSV reads business volumes from a CSV file with a header row and the
+// columns period, metric and value. Periods are a day (2026-09-14) or a calendar
+// month (2026-09).
+//
+//	period,metric,value
+//	2026-09,requests,125000000
+//	2026-09,customers,1830
+func LoadBusinessCSV(path string) (Business, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening business CSV: %w", err)
+	}
+	defer file.Close()
+	return ReadBusinessCSV(file)
+}
+
+// ReadBusinessCSV reads business volumes in the format of LoadBusinessCSV
+func ReadBusinessCSV(r io.Reader) (Business, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading business CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return Business{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"period", "metric", "value"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("business CSV has no %s column", name)
+		}
+	}
+
+	business := make(Business)
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		start, end, err := parseBusinessPeriod(field("period"))
+		if err != nil {
+			return nil, fmt.Errorf("business CSV line %d: %w", line+2, err)
+		}
+		value, err := strconv.ParseFloat(field("value"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("business CSV line %d: invalid value %q", line+2, field("value"))
+		}
+		metric := strings.ToLower(field("metric"))
+		business[metric] = append(business[metric], BusinessValue{Start: start, End: end, Value: value})
+	}
+
+	return business, nil
+}
+
+func parseBusinessPeriod(period string) (time.Time, time.Time, error) {
+	if day, err := time.Parse("2006-01-02", period); err == nil {
+		return day, day.AddDate(0, 0, 1), nil
+	}
+	if month, err := time.Parse("2006-01", period); err == nil {
+		return month, month.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q (use YYYY-MM-DD or YYYY-MM)", period)
+}
+
+// Total sums a metric over start to end (exclusive). Values that only partly overlap
+// the range, such as a monthly value for a two-week period, count pro rata. ok is
+// false when no value overlaps the range.
+func (b Business) Total(metric string, start, end time.Time) (float64, bool) {
+	total, found := 0.0, false
+	for _, v := range b[strings.ToLower(metric)] {
+		from, to := v.Start, v.End
+		if start.After(from) {
+			from = start
+		}
+		if end.Before(to) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+		total += v.Value * to.Sub(from).Hours() / v.End.Sub(v.Start).Hours()
+		found = true
+	}
+	return total, found
+}
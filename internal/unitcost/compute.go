@@ -0,0 +1,56 @@
+package unitcost
+
+import (
+	"time"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+)
+
+// Value is a KPI computed over one period
+type Value struct {
+	KPI      KPI
+	Start    time.Time
+	End      time.Time // Exclusive
+	Cost     float64
+	Currency string
+	Quantity float64  // Usage or business volume the cost is divided by
+	Value    *float64 // Cost per KPI.Per units; nil without a quantity
+}
+
+// Compute computes the KPIs that apply to a provider from the cost and usage fetched
+// for one period and the business volumes of that period
+func Compute(kpis []KPI, provider string, costs []providers.CostData, usage []providers.UsageData, business Business, start, end time.Time) []Value {
+	values := make([]Value, 0, len(kpis))
+	for _, k := range kpis {
+		if !k.appliesTo(provider) {
+			continue
+		}
+
+		v := Value{KPI: k, Start: start, End: end}
+		for _, c := range costs {
+			if k.Cost.matches(c) {
+				v.Cost += c.Cost
+				if v.Currency == "" {
+					v.Currency = c.Currency
+				}
+			}
+		}
+
+		if k.Business != "" {
+			v.Quantity, _ = business.Total(k.Business, start, end)
+		} else {
+			for _, u := range usage {
+				if k.Usage.matches(u) {
+					v.Quantity += u.Value
+				}
+			}
+		}
+
+		if v.Quantity > 0 {
+			perUnit := v.Cost / v.Quantity * k.per()
+			v.Value = &perUnit
+		}
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,163 @@
+// Package unitcost derives unit economics KPIs, such as cost per GB ingested or per
+// active user, by joining the cost and usage of the same services and period, or by
+// dividing cost by business volumes such as requests served.
+package unitcost
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ilhicas/observability-cost-center/internal/providers"
+	"github.com/spf13/viper"
+)
+
+// CostSelector selects the cost entries of a KPI. Fields are patterns as in
+// providers.MatchPattern; empty fields match everything.
+type CostSelector struct {
+	Account string `mapstructure:"account"`
+	Service string `mapstructure:"service"`
+	Item    string `mapstructure:"item"`
+}
+
+// UsageSelector selects the usage entries that make up a KPI's denominator
+type UsageSelector struct {
+	Service string `mapstructure:"service"`
+	Metric  string `mapstructure:"metric"`
+	Unit    string `mapstructure:"unit"`
+}
+
+// KPI divides the cost it selects by a usage quantity or a business metric
+type KPI struct {
+	Name     string        `mapstructure:"name"`
+	Title    string        `mapstructure:"title"`
+	Provider string        `mapstructure:"provider"` // Empty applies to every provider
+	Cost     CostSelector  `mapstructure:"cost"`
+	Usage    UsageSelector `mapstructure:"usage"`
+
+	// Business names a metric of the business CSV to divide by instead of usage
+	Business string `mapstructure:"business"`
+
+	// Per is the number of denominator units the cost is given for, e.g. 1000000 for
+	// cost per million spans. Unit names the denominator unit.
+	Per  float64 `mapstructure:"per"`
+	Unit string  `mapstructure:"unit"`
+}
+
+// ActiveUsersMetric is the usage metric of the active New Relic users, which the
+// unit-costs command adds from the license data
+const ActiveUsersMetric = "Active Users"
+
+// DefaultKPIs are computed when none are configured. AWS has none: Cost Explorer
+// bills CloudWatch per account rather than per usage type, so its cost cannot be
+// split between logs and metrics.
+func DefaultKPIs() []KPI {
+	return []KPI{
+		{
+			Name:     "newrelic_cost_per_gb_ingested",
+			Title:    "New Relic ingest cost per GB",
+			Provider: "newrelic",
+			Cost:     CostSelector{Item: "GigabytesIngested"},
+			Usage:    UsageSelector{Metric: "DataSize", Unit: "GB"},
+			Unit:     "GB",
+		},
+		{
+			Name:     "newrelic_cost_per_active_user",
+			Title:    "New Relic license cost per active user",
+			Provider: "newrelic",
+			Cost:     CostSelector{Service: "Licenses"},
+			Usage:    UsageSelector{Service: "Licenses", Metric: ActiveUsersMetric},
+			Unit:     "active user",
+		},
+	}
+}
+
+// KPIsFromViper reads unit_economics.kpis from the configuration, falling back to the
+// defaults when none are configured.
+//
+//	unit_economics:
+//	  business_csv: business.csv
+//	  kpis:
+//	    - name: cost_per_million_spans
+//	      provider: newrelic
+//	      cost: {item: GigabytesIngested}
+//	      usage: {metric: Spans}
+//	      per: 1000000
+//	      unit: spans
+//	    - name: cost_per_customer
+//	      business: customers
+//	      unit: customer
+func KPIsFromViper(config *viper.Viper) ([]KPI, error) {
+	var kpis []KPI
+	if err := config.UnmarshalKey("unit_economics.kpis", &kpis); err != nil {
+		return nil, fmt.Errorf("error reading unit economics KPIs: %w", err)
+	}
+	if len(kpis) == 0 {
+		return DefaultKPIs(), nil
+	}
+
+	names := make(map[string]bool, len(kpis))
+	for i := range kpis {
+		k := &kpis[i]
+		if k.Name == "" {
+			return nil, fmt.Errorf("unit economics KPI %d has no name", i+1)
+		}
+		if names[k.Name] {
+			return nil, fmt.Errorf("duplicate unit economics KPI %q", k.Name)
+		}
+		names[k.Name] = true
+		if k.Business == "" && k.Usage.Metric == "" {
+			return nil, fmt.Errorf("KPI %q needs a usage metric or a business metric", k.Name)
+		}
+		if k.Per < 0 {
+			return nil, fmt.Errorf("KPI %q: per must be positive, got %g", k.Name, k.Per)
+		}
+		k.Provider = strings.ToLower(k.Provider)
+	}
+	return kpis, nil
+}
+
+// Label is the title of the KPI, or its name
+func (k KPI) Label() string {
+	title := k.Title
+	if title == "" {
+		title = k.Name
+	}
+	return title
+}
+
+// UnitLabel describes what the cost is given for, e.g. "per 1000000 spans"
+func (k KPI) UnitLabel() string {
+	unit := k.Unit
+	if unit == "" {
+		unit = "unit"
+	}
+	if k.per() == 1 {
+		return "per " + unit
+	}
+	return fmt.Sprintf("per %s %s", strconv.FormatFloat(k.per(), 'f', -1, 64), unit)
+}
+
+func (k KPI) per() float64 {
+	if k.Per > 0 {
+		return k.Per
+	}
+	return 1
+}
+
+// appliesTo reports whether the KPI is computed for a provider
+func (k KPI) appliesTo(provider string) bool {
+	return k.Provider == "" || strings.EqualFold(k.Provider, provider)
+}
+
+func (s CostSelector) matches(c providers.CostData) bool {
+	return providers.MatchPattern(s.Account, c.AccountID) &&
+		providers.MatchPattern(s.Service, c.Service) &&
+		providers.MatchPattern(s.Item, c.ItemName)
+}
+
+func (s UsageSelector) matches(u providers.UsageData) bool {
+	return providers.MatchPattern(s.Service, u.Service) &&
+		providers.MatchPattern(s.Metric, u.Metric) &&
+		providers.MatchPattern(s.Unit, u.Unit)
+}